@@ -0,0 +1,136 @@
+package bot
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/player"
+	"github.com/ivan1993spb/snake-server/profile"
+	"github.com/ivan1993spb/snake-server/teams"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+// Interval of checking whether bot can get new snake
+const spawnCheckInterval = time.Millisecond * 250
+
+// Bot plays like player without connection: it gets snake, turns the snake using strategy and gets new snake after
+// death. Bots have unlimited lives and respawn automatically
+type Bot struct {
+	world    *world.World
+	profile  *profile.Profile
+	team     *teams.Team
+	policy   player.SpawnPolicy
+	respawn  player.Respawn
+	strategy Strategy
+	logger   logrus.FieldLogger
+}
+
+func NewBot(logger logrus.FieldLogger, world *world.World, profile *profile.Profile, team *teams.Team,
+	policy player.SpawnPolicy, respawn player.Respawn, strategy Strategy) *Bot {
+	return &Bot{
+		logger:   logger,
+		world:    world,
+		profile:  profile,
+		team:     team,
+		policy:   policy,
+		respawn:  respawn,
+		strategy: strategy,
+	}
+}
+
+// Start runs bot until stop is closed. Returned channel is closed when bot is stopped and its snake is dead
+func (b *Bot) Start(stop <-chan struct{}) <-chan struct{} {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for {
+			if !b.wait(stop, b.respawn.Delay) {
+				return
+			}
+
+			if !b.waitSpawn(stop) {
+				return
+			}
+
+			s, err := b.respawn.Spawn(b.world, b.profile, b.team)
+			if err != nil {
+				b.logger.Errorln("cannot create snake to bot:", err)
+				if !b.wait(stop, spawnCheckInterval) {
+					return
+				}
+				continue
+			}
+
+			b.control(stop, s)
+		}
+	}()
+
+	return done
+}
+
+// control turns snake using strategy after each move until the snake dies
+func (b *Bot) control(stop <-chan struct{}, s *snake.Snake) {
+	snakeDone := s.Run(stop)
+
+	for {
+		select {
+		case <-s.Moved():
+			if command := b.strategy.Command(newView(b.world, s)); command != "" {
+				// Strategy may suggest turn which snake cannot make
+				s.Command(command)
+			}
+		case <-snakeDone:
+			return
+		case <-stop:
+			<-snakeDone
+			return
+		}
+	}
+}
+
+// wait blocks for passed duration. It returns false if bot is stopped
+func (b *Bot) wait(stop <-chan struct{}, duration time.Duration) bool {
+	if duration <= 0 {
+		select {
+		case <-stop:
+			return false
+		default:
+			return true
+		}
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-stop:
+		return false
+	}
+}
+
+// waitSpawn blocks until spawn policy allows new snake. It returns false if bot is stopped
+func (b *Bot) waitSpawn(stop <-chan struct{}) bool {
+	if b.policy.AllowSpawn() {
+		return true
+	}
+
+	ticker := time.NewTicker(spawnCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if b.policy.AllowSpawn() {
+				return true
+			}
+		case <-stop:
+			return false
+		}
+	}
+}
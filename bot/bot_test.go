@@ -0,0 +1,54 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/player"
+	"github.com/ivan1993spb/snake-server/profile"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+func Test_Bot_Start_SpawnsSnakeAndStops(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	w, err := world.NewWorld(20, 20)
+	require.Nil(t, err)
+
+	worldStop := make(chan struct{})
+	defer close(worldStop)
+	w.Start(worldStop)
+
+	p := &profile.Profile{
+		ID:       "bot",
+		Nickname: "bot 1",
+		Bot:      true,
+	}
+
+	stop := make(chan struct{})
+	done := NewBot(logger, w, p, nil, spawnPolicy(true), player.Respawn{}, randomWalker{}).Start(stop)
+
+	var s *snake.Snake
+	for i := 0; i < 100 && s == nil; i++ {
+		for _, object := range w.GetObjects() {
+			if found, ok := object.(*snake.Snake); ok {
+				s = found
+			}
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	require.NotNil(t, s, "bot gets snake")
+	require.True(t, s.GetProfile().Bot)
+
+	close(stop)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("bot is not stopped")
+	}
+	require.False(t, w.ObjectExists(s), "snake of stopped bot dies")
+}
@@ -0,0 +1,58 @@
+package bot
+
+import (
+	"errors"
+)
+
+// Difficulty is skill level of bot which defines strategy of the bot
+type Difficulty uint8
+
+const (
+	// DifficultyEasy bots wander at random
+	DifficultyEasy Difficulty = iota
+	// DifficultyNormal bots go to the nearest food
+	DifficultyNormal
+	// DifficultyHard bots avoid dead ends and heads of other snakes
+	DifficultyHard
+	// DifficultyInsane bots hunt heads of other snakes
+	DifficultyInsane
+)
+
+var difficultyLabels = map[Difficulty]string{
+	DifficultyEasy:   "easy",
+	DifficultyNormal: "normal",
+	DifficultyHard:   "hard",
+	DifficultyInsane: "insane",
+}
+
+func (d Difficulty) String() string {
+	if label, ok := difficultyLabels[d]; ok {
+		return label
+	}
+	return "unknown"
+}
+
+var ErrUnknownDifficulty = errors.New("unknown bot difficulty")
+
+// ParseDifficulty returns difficulty by label
+func ParseDifficulty(label string) (Difficulty, error) {
+	for difficulty, difficultyLabel := range difficultyLabels {
+		if difficultyLabel == label {
+			return difficulty, nil
+		}
+	}
+	return 0, ErrUnknownDifficulty
+}
+
+// Strategy returns new strategy of bot of the difficulty
+func (d Difficulty) Strategy() Strategy {
+	switch d {
+	case DifficultyEasy:
+		return NewRandomWalker()
+	case DifficultyHard:
+		return NewCautious()
+	case DifficultyInsane:
+		return NewHunter()
+	}
+	return NewGreedy()
+}
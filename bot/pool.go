@@ -0,0 +1,230 @@
+package bot
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+
+	"github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/player"
+	"github.com/ivan1993spb/snake-server/profile"
+	"github.com/ivan1993spb/snake-server/teams"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+// Maximal count of players which bots can top up game to
+const maxTarget = 64
+
+// Config contains settings of bots of game
+type Config struct {
+	// Target is count of players which bots top up game to. Zero disables bots
+	Target int
+	// Difficulties is mix of difficulties of bots: n-th running bot has difficulty n modulo length of the mix. Empty
+	// mix means normal bots
+	Difficulties []Difficulty
+}
+
+type ErrInvalidConfig string
+
+func (e ErrInvalidConfig) Error() string {
+	return "invalid bots config: " + string(e)
+}
+
+// Validate checks settings of bots
+func (c Config) Validate() error {
+	if c.Target < 0 || c.Target > maxTarget {
+		return ErrInvalidConfig("target out of range")
+	}
+	if len(c.Difficulties) > maxTarget {
+		return ErrInvalidConfig("too many difficulties")
+	}
+	for _, difficulty := range c.Difficulties {
+		if _, ok := difficultyLabels[difficulty]; !ok {
+			return ErrInvalidConfig("unknown difficulty")
+		}
+	}
+	return nil
+}
+
+// difficulty returns difficulty of n-th running bot
+func (c Config) difficulty(n int) Difficulty {
+	if len(c.Difficulties) == 0 {
+		return DifficultyNormal
+	}
+	return c.Difficulties[n%len(c.Difficulties)]
+}
+
+// running is started bot
+type running struct {
+	stop chan struct{}
+	done <-chan struct{}
+	team *teams.Team
+}
+
+// Pool keeps count of players in game not less than target: it starts bots when players leave and stops bots when
+// players join
+type Pool struct {
+	config  Config
+	world   *world.World
+	teams   *teams.Teams
+	policy  player.SpawnPolicy
+	respawn player.Respawn
+	logger  logrus.FieldLogger
+
+	bots    []*running
+	humans  int
+	limit   int
+	counter int
+	started bool
+	closed  bool
+	mux     *sync.Mutex
+}
+
+// NewPool creates pool of bots. Teams are nil if game has no teams
+func NewPool(logger logrus.FieldLogger, world *world.World, gameTeams *teams.Teams, policy player.SpawnPolicy,
+	respawn player.Respawn, config Config) (*Pool, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &Pool{
+		config:  config,
+		world:   world,
+		teams:   gameTeams,
+		policy:  policy,
+		respawn: respawn,
+		logger:  logger,
+		bots:    make([]*running, 0, config.Target),
+		mux:     &sync.Mutex{},
+	}, nil
+}
+
+// Start runs bots until stop is closed
+func (p *Pool) Start(stop <-chan struct{}) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	if p.started {
+		return
+	}
+	p.started = true
+	p.unsafeBalance()
+
+	go func() {
+		<-stop
+
+		p.mux.Lock()
+		defer p.mux.Unlock()
+
+		p.closed = true
+		for len(p.bots) > 0 {
+			p.unsafeStopBot()
+		}
+	}()
+}
+
+// Balance starts or stops bots to keep passed count of human players and bots equal to target
+func (p *Pool) Balance(humans int) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	p.humans = humans
+	if p.started {
+		p.unsafeBalance()
+	}
+}
+
+// SetLimit sets player limit of game. Bots never top up game over the limit. Zero means no limit
+func (p *Pool) SetLimit(limit int) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	p.limit = limit
+	if p.started {
+		p.unsafeBalance()
+	}
+}
+
+func (p *Pool) unsafeBalance() {
+	if p.closed {
+		return
+	}
+
+	target := p.config.Target
+	if p.limit > 0 && target > p.limit {
+		target = p.limit
+	}
+
+	need := target - p.humans
+	if need < 0 {
+		need = 0
+	}
+
+	for len(p.bots) < need {
+		if !p.unsafeStartBot() {
+			break
+		}
+	}
+
+	for len(p.bots) > need {
+		p.unsafeStopBot()
+	}
+}
+
+func (p *Pool) unsafeStartBot() bool {
+	var team *teams.Team
+
+	if p.teams != nil {
+		var err error
+		if team, err = p.teams.Join(""); err != nil {
+			p.logger.WithError(err).Error("cannot join team to bot")
+			return false
+		}
+	}
+
+	difficulty := p.config.difficulty(len(p.bots))
+	p.counter++
+
+	botProfile := &profile.Profile{
+		ID:       uuid.Must(uuid.NewV4()).String(),
+		Nickname: fmt.Sprintf("bot %d", p.counter),
+		Skin:     uint8(rand.Intn(profile.SkinsLimit)),
+		Bot:      true,
+	}
+
+	stop := make(chan struct{})
+	b := NewBot(p.logger, p.world, botProfile, team, p.policy, p.respawn,
+		difficulty.Strategy())
+
+	p.bots = append(p.bots, &running{
+		stop: stop,
+		done: b.Start(stop),
+		team: team,
+	})
+
+	return true
+}
+
+// unsafeStopBot stops the last started bot
+func (p *Pool) unsafeStopBot() {
+	r := p.bots[len(p.bots)-1]
+	p.bots = p.bots[:len(p.bots)-1]
+
+	close(r.stop)
+
+	if r.team != nil {
+		go func() {
+			<-r.done
+			p.teams.Leave(r.team)
+		}()
+	}
+}
+
+// Count returns count of running bots
+func (p *Pool) Count() int {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	return len(p.bots)
+}
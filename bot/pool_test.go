@@ -0,0 +1,94 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/player"
+	"github.com/ivan1993spb/snake-server/teams"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+type spawnPolicy bool
+
+func (p spawnPolicy) AllowSpawn() bool {
+	return bool(p)
+}
+
+func Test_Config_Validate(t *testing.T) {
+	require.Nil(t, Config{}.Validate())
+	require.Nil(t, Config{Target: 10}.Validate())
+	require.Equal(t, ErrInvalidConfig("target out of range"), Config{Target: -1}.Validate())
+	require.Equal(t, ErrInvalidConfig("target out of range"), Config{Target: maxTarget + 1}.Validate())
+	require.Nil(t, Config{Target: 2, Difficulties: []Difficulty{DifficultyEasy, DifficultyInsane}}.Validate())
+	require.Equal(t, ErrInvalidConfig("unknown difficulty"), Config{Difficulties: []Difficulty{10}}.Validate())
+}
+
+func Test_Pool_SetLimit_CapsBotsAtPlayerLimit(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	w, err := world.NewWorld(20, 20)
+	require.Nil(t, err)
+
+	pool, err := NewPool(logger, w, nil, spawnPolicy(false), player.Respawn{}, Config{Target: 10})
+	require.Nil(t, err)
+	pool.SetLimit(4)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	pool.Start(stop)
+	require.Equal(t, 4, pool.Count())
+
+	pool.Balance(1)
+	require.Equal(t, 3, pool.Count())
+
+	pool.SetLimit(0)
+	require.Equal(t, 9, pool.Count(), "zero means no limit")
+}
+
+func Test_Config_difficulty_MixesDifficulties(t *testing.T) {
+	require.Equal(t, DifficultyNormal, Config{}.difficulty(3))
+
+	config := Config{Difficulties: []Difficulty{DifficultyEasy, DifficultyHard}}
+	require.Equal(t, DifficultyEasy, config.difficulty(0))
+	require.Equal(t, DifficultyHard, config.difficulty(1))
+	require.Equal(t, DifficultyEasy, config.difficulty(2))
+}
+
+func Test_Pool_Balance_TopsUpPlayersToTarget(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	w, err := world.NewWorld(20, 20)
+	require.Nil(t, err)
+	gameTeams, err := teams.NewTeams(2, true)
+	require.Nil(t, err)
+
+	pool, err := NewPool(logger, w, gameTeams, spawnPolicy(false), player.Respawn{}, Config{Target: 4})
+	require.Nil(t, err)
+
+	pool.Balance(1)
+	require.Equal(t, 0, pool.Count(), "bots are not started before pool")
+
+	stop := make(chan struct{})
+	pool.Start(stop)
+	require.Equal(t, 3, pool.Count())
+
+	pool.Balance(3)
+	require.Equal(t, 1, pool.Count(), "bots leave as players join")
+
+	pool.Balance(6)
+	require.Equal(t, 0, pool.Count())
+
+	pool.Balance(0)
+	require.Equal(t, 4, pool.Count())
+
+	close(stop)
+	for i := 0; i < 100 && pool.Count() > 0; i++ {
+		time.Sleep(time.Millisecond * 10)
+	}
+	require.Equal(t, 0, pool.Count(), "bots are stopped with pool")
+
+	pool.Balance(0)
+	require.Equal(t, 0, pool.Count(), "stopped pool does not start bots")
+}
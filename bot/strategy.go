@@ -0,0 +1,293 @@
+package bot
+
+import (
+	"math/rand"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects/snake"
+)
+
+// Strategy decides where snake of bot goes. Strategy is called after each move of snake with snapshot of playground
+// around the snake. Strategies do not change the view
+type Strategy interface {
+	// Command returns command to snake or empty command to keep movement direction
+	Command(view *View) snake.Command
+}
+
+var directions = []engine.Direction{
+	engine.DirectionNorth,
+	engine.DirectionEast,
+	engine.DirectionSouth,
+	engine.DirectionWest,
+}
+
+var directionCommands = map[engine.Direction]snake.Command{
+	engine.DirectionNorth: snake.CommandToNorth,
+	engine.DirectionEast:  snake.CommandToEast,
+	engine.DirectionSouth: snake.CommandToSouth,
+	engine.DirectionWest:  snake.CommandToWest,
+}
+
+const (
+	// Chance of random walker to turn when the way ahead is safe
+	randomWalkerTurnChance = 0.1
+	// Maximal count of steps in search of path to target
+	searchDepth = viewRadius * 2
+	// Maximal count of dots counted by flood fill
+	floodLimit = 64
+)
+
+// randomWalker keeps movement direction of snake and turns at random or when the way ahead is not safe
+type randomWalker struct{}
+
+// NewRandomWalker returns strategy which wanders and only avoids obstacles right ahead
+func NewRandomWalker() Strategy {
+	return randomWalker{}
+}
+
+func (randomWalker) Command(view *View) snake.Command {
+	safe := safeDirections(view)
+	if len(safe) == 0 {
+		return ""
+	}
+
+	for _, dir := range safe {
+		if dir == view.Direction() && rand.Float64() >= randomWalkerTurnChance {
+			return ""
+		}
+	}
+
+	return turn(view, safe[rand.Intn(len(safe))])
+}
+
+// greedy goes by the shortest path to the nearest food
+type greedy struct{}
+
+// NewGreedy returns strategy which goes to the nearest food and wanders if it does not see food
+func NewGreedy() Strategy {
+	return greedy{}
+}
+
+func (greedy) Command(view *View) snake.Command {
+	if dir, ok := search(view, safeDirections(view), isCell(view, CellFood)); ok {
+		return turn(view, dir)
+	}
+	return randomWalker{}.Command(view)
+}
+
+// cautious goes to food only if the snake does not get in dead end and keeps away from heads of other snakes
+type cautious struct{}
+
+// NewCautious returns strategy which survives first: it goes to the nearest food if there is enough space behind the
+// food and otherwise goes to the widest space
+func NewCautious() Strategy {
+	return cautious{}
+}
+
+func (cautious) Command(view *View) snake.Command {
+	dirs := calmDirections(view)
+	if len(dirs) == 0 {
+		return ""
+	}
+
+	roomy := roomyDirections(view, dirs)
+
+	if dir, ok := search(view, roomy, isCell(view, CellFood)); ok {
+		return turn(view, dir)
+	}
+
+	return turn(view, widestDirection(view, dirs))
+}
+
+// hunter goes to heads of other snakes to cut them off and eats food when there are no snakes around
+type hunter struct{}
+
+// NewHunter returns strategy which attacks heads of other snakes if there is enough space to escape and plays like
+// cautious strategy otherwise
+func NewHunter() Strategy {
+	return hunter{}
+}
+
+func (hunter) Command(view *View) snake.Command {
+	roomy := roomyDirections(view, safeDirections(view))
+
+	if dir, ok := search(view, roomy, nearHead(view)); ok {
+		return turn(view, dir)
+	}
+
+	return cautious{}.Command(view)
+}
+
+// turn returns command to move in passed direction or empty command if snake already moves in the direction
+func turn(view *View, dir engine.Direction) snake.Command {
+	if dir == view.Direction() {
+		return ""
+	}
+	return directionCommands[dir]
+}
+
+// safeDirections returns directions from head in which the next dot is passable. Reverse direction of current
+// movement is skipped. Current direction goes first
+func safeDirections(view *View) []engine.Direction {
+	current := view.Direction()
+	reverse, _ := current.Reverse()
+	safe := make([]engine.Direction, 0, len(directions))
+
+	if view.Passable(view.Navigate(view.Head(), current)) {
+		safe = append(safe, current)
+	}
+
+	for _, dir := range directions {
+		if dir == reverse || dir == current {
+			continue
+		}
+		if view.Passable(view.Navigate(view.Head(), dir)) {
+			safe = append(safe, dir)
+		}
+	}
+
+	return safe
+}
+
+// calmDirections returns safe directions which do not lead next to heads of other snakes. If all safe directions
+// are near heads, all safe directions are returned
+func calmDirections(view *View) []engine.Direction {
+	safe := safeDirections(view)
+	calm := make([]engine.Direction, 0, len(safe))
+	danger := nearHead(view)
+
+	for _, dir := range safe {
+		if !danger(view.Navigate(view.Head(), dir)) {
+			calm = append(calm, dir)
+		}
+	}
+
+	if len(calm) == 0 {
+		return safe
+	}
+	return calm
+}
+
+// roomyDirections returns directions in which there is enough space for snake to turn around
+func roomyDirections(view *View, dirs []engine.Direction) []engine.Direction {
+	need := int(view.Length())
+	if need > floodLimit {
+		need = floodLimit
+	}
+
+	roomy := make([]engine.Direction, 0, len(dirs))
+	for _, dir := range dirs {
+		if space(view, view.Navigate(view.Head(), dir)) >= need {
+			roomy = append(roomy, dir)
+		}
+	}
+	return roomy
+}
+
+// widestDirection returns direction with the largest space. The first direction wins on equal spaces
+func widestDirection(view *View, dirs []engine.Direction) engine.Direction {
+	best := dirs[0]
+	bestSpace := -1
+
+	for _, dir := range dirs {
+		if s := space(view, view.Navigate(view.Head(), dir)); s > bestSpace {
+			best = dir
+			bestSpace = s
+		}
+	}
+
+	return best
+}
+
+// isCell returns goal function which matches dots with passed cell
+func isCell(view *View, cell Cell) func(engine.Dot) bool {
+	return func(dot engine.Dot) bool {
+		return view.Cell(dot) == cell
+	}
+}
+
+// nearHead returns goal function which matches dots next to heads of other snakes
+func nearHead(view *View) func(engine.Dot) bool {
+	return func(dot engine.Dot) bool {
+		for _, dir := range directions {
+			if view.Cell(view.Navigate(dot, dir)) == CellHead {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// search finds the shortest path from head to dot matching goal through passable dots. The path starts in one of
+// passed directions. It returns the first direction of the path
+func search(view *View, dirs []engine.Direction, goal func(engine.Dot) bool) (engine.Direction, bool) {
+	type step struct {
+		dot   engine.Dot
+		first engine.Direction
+	}
+
+	visited := map[engine.Dot]bool{
+		view.Head(): true,
+	}
+	queue := make([]step, 0, len(dirs))
+
+	for _, dir := range dirs {
+		dot := view.Navigate(view.Head(), dir)
+		if !visited[dot] {
+			visited[dot] = true
+			queue = append(queue, step{dot, dir})
+		}
+	}
+
+	for depth := 0; depth < searchDepth && len(queue) > 0; depth++ {
+		next := make([]step, 0, len(queue)*2)
+
+		for _, s := range queue {
+			if goal(s.dot) {
+				return s.first, true
+			}
+
+			for _, dir := range directions {
+				dot := view.Navigate(s.dot, dir)
+				if !visited[dot] && view.Passable(dot) {
+					visited[dot] = true
+					next = append(next, step{dot, s.first})
+				}
+			}
+		}
+
+		queue = next
+	}
+
+	return 0, false
+}
+
+// space counts passable dots reachable from passed dot using flood fill. Counting stops at floodLimit
+func space(view *View, from engine.Dot) int {
+	if !view.Passable(from) {
+		return 0
+	}
+
+	visited := map[engine.Dot]bool{
+		view.Head(): true,
+		from:        true,
+	}
+	queue := []engine.Dot{from}
+	count := 0
+
+	for len(queue) > 0 && count < floodLimit {
+		dot := queue[0]
+		queue = queue[1:]
+		count++
+
+		for _, dir := range directions {
+			next := view.Navigate(dot, dir)
+			if !visited[next] && view.Passable(next) {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return count
+}
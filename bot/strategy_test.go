@@ -0,0 +1,128 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects/snake"
+)
+
+func Test_safeDirections_SkipsObstaclesAndReverse(t *testing.T) {
+	view, err := NewSnapshot([]string{
+		".....",
+		"..#..",
+		"..@*.",
+		".....",
+	}, engine.DirectionNorth, 3)
+	require.Nil(t, err)
+
+	safe := safeDirections(view)
+	require.Equal(t, []engine.Direction{engine.DirectionEast, engine.DirectionWest}, safe)
+}
+
+func Test_RandomWalker_Command_AvoidsObstacles(t *testing.T) {
+	view, err := NewSnapshot([]string{
+		".#.",
+		"#@.",
+		".#.",
+	}, engine.DirectionNorth, 3)
+	require.Nil(t, err)
+
+	strategy := NewRandomWalker()
+	for i := 0; i < 20; i++ {
+		require.Equal(t, snake.CommandToEast, strategy.Command(view))
+	}
+}
+
+func Test_Greedy_Command_GoesToNearestFood(t *testing.T) {
+	view, err := NewSnapshot([]string{
+		".......",
+		"...*...",
+		".......",
+		"...@.*.",
+		".......",
+	}, engine.DirectionSouth, 3)
+	require.Nil(t, err)
+	require.Equal(t, snake.CommandToEast, NewGreedy().Command(view))
+
+	view, err = NewSnapshot([]string{
+		"..@...*",
+	}, engine.DirectionNorth, 3)
+	require.Nil(t, err)
+	require.Equal(t, snake.CommandToWest, NewGreedy().Command(view), "playground is a torus")
+
+	view, err = NewSnapshot([]string{
+		".......",
+		"...*...",
+		"...@...",
+	}, engine.DirectionNorth, 3)
+	require.Nil(t, err)
+	require.Equal(t, snake.Command(""), NewGreedy().Command(view), "snake already goes to food")
+}
+
+func Test_Cautious_Command_AvoidsDeadEnds(t *testing.T) {
+	view, err := NewSnapshot([]string{
+		"##########",
+		"#*.@.....#",
+		"####.....#",
+		"####.....#",
+		"##########",
+	}, engine.DirectionNorth, 10)
+	require.Nil(t, err)
+
+	require.Equal(t, snake.CommandToWest, NewGreedy().Command(view))
+	require.Equal(t, snake.CommandToEast, NewCautious().Command(view), "food is in dead end")
+}
+
+func Test_Cautious_Command_KeepsAwayFromHeads(t *testing.T) {
+	view, err := NewSnapshot([]string{
+		".......",
+		"...X...",
+		".......",
+		"...@...",
+		".......",
+	}, engine.DirectionNorth, 3)
+	require.Nil(t, err)
+
+	command := NewCautious().Command(view)
+	require.NotEqual(t, snake.Command(""), command)
+	require.NotEqual(t, snake.CommandToNorth, command)
+}
+
+func Test_Hunter_Command_GoesToHeads(t *testing.T) {
+	view, err := NewSnapshot([]string{
+		"............",
+		"............",
+		"...@....X...",
+		"............",
+	}, engine.DirectionNorth, 3)
+	require.Nil(t, err)
+	require.Equal(t, snake.CommandToEast, NewHunter().Command(view))
+
+	view, err = NewSnapshot([]string{
+		".......",
+		".......",
+		"...@.*.",
+		".......",
+	}, engine.DirectionNorth, 3)
+	require.Nil(t, err)
+	require.Equal(t, snake.CommandToEast, NewHunter().Command(view), "hunter eats food when there are no snakes")
+}
+
+func Test_Difficulty_Strategy(t *testing.T) {
+	require.Equal(t, NewRandomWalker(), DifficultyEasy.Strategy())
+	require.Equal(t, NewGreedy(), DifficultyNormal.Strategy())
+	require.Equal(t, NewCautious(), DifficultyHard.Strategy())
+	require.Equal(t, NewHunter(), DifficultyInsane.Strategy())
+
+	for difficulty, label := range difficultyLabels {
+		parsed, err := ParseDifficulty(label)
+		require.Nil(t, err)
+		require.Equal(t, difficulty, parsed)
+	}
+
+	_, err := ParseDifficulty("godlike")
+	require.Equal(t, ErrUnknownDifficulty, err)
+}
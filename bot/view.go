@@ -0,0 +1,208 @@
+package bot
+
+import (
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects"
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+// Radius in dots of area around head of snake which bot sees
+const viewRadius = 12
+
+// Cell is content of dot of playground seen by bot
+type Cell uint8
+
+const (
+	// CellEmpty is free dot or dot which bot does not see
+	CellEmpty Cell = iota
+	// CellFood is dot with edible object
+	CellFood
+	// CellObstacle is dot with object which kills snake: wall, body of snake, bomb and others
+	CellObstacle
+	// CellHead is dot with head of another snake
+	CellHead
+)
+
+// Symbols of cells in playground snapshot
+const (
+	symbolEmpty    = '.'
+	symbolFood     = '*'
+	symbolObstacle = '#'
+	symbolHead     = 'X'
+	symbolSelf     = '@'
+)
+
+var symbolCells = map[rune]Cell{
+	symbolEmpty:    CellEmpty,
+	symbolFood:     CellFood,
+	symbolObstacle: CellObstacle,
+	symbolHead:     CellHead,
+}
+
+// View is read-only snapshot of playground around snake of bot
+type View struct {
+	area      engine.Area
+	cells     map[engine.Dot]Cell
+	head      engine.Dot
+	direction engine.Direction
+	length    uint16
+}
+
+// newView takes snapshot of dots around head of snake. Body of the snake is seen as obstacle
+func newView(w *world.World, s *snake.Snake) *View {
+	location := s.GetLocation()
+	area, _ := engine.NewArea(w.Width(), w.Height())
+
+	v := &View{
+		area:      area,
+		cells:     map[engine.Dot]Cell{},
+		direction: s.GetDirection(),
+		length:    s.GetLength(),
+	}
+	if len(location) == 0 {
+		return v
+	}
+	v.head = location[0]
+
+	for dx := -viewRadius; dx <= viewRadius; dx++ {
+		for dy := -viewRadius; dy <= viewRadius; dy++ {
+			dot, err := w.Shift(v.head, dx, dy)
+			if err != nil || dot == v.head {
+				continue
+			}
+			if cell := cellOf(w.GetObjectByDot(dot), dot); cell != CellEmpty {
+				v.cells[dot] = cell
+			}
+		}
+	}
+
+	return v
+}
+
+// cellOf returns cell by object on dot
+func cellOf(object interface{}, dot engine.Dot) Cell {
+	if object == nil {
+		return CellEmpty
+	}
+	if _, ok := object.(objects.Food); ok {
+		return CellFood
+	}
+	if s, ok := object.(*snake.Snake); ok {
+		if location := s.GetLocation(); len(location) > 0 && location[0] == dot {
+			return CellHead
+		}
+	}
+	return CellObstacle
+}
+
+type ErrInvalidSnapshot string
+
+func (e ErrInvalidSnapshot) Error() string {
+	return "invalid playground snapshot: " + string(e)
+}
+
+// NewSnapshot creates view from text rows of playground. Symbol '.' is empty dot, '*' is food, '#' is obstacle, 'X'
+// is head of another snake and '@' is head of snake of bot. Playground is a torus like game playground. Snapshots let
+// test strategies without running game
+func NewSnapshot(rows []string, direction engine.Direction, length uint16) (*View, error) {
+	if len(rows) == 0 || len(rows) > 255 {
+		return nil, ErrInvalidSnapshot("height out of range")
+	}
+
+	width := len([]rune(rows[0]))
+	if width == 0 || width > 255 {
+		return nil, ErrInvalidSnapshot("width out of range")
+	}
+
+	area, err := engine.NewArea(uint8(width), uint8(len(rows)))
+	if err != nil {
+		return nil, ErrInvalidSnapshot(err.Error())
+	}
+
+	v := &View{
+		area:      area,
+		cells:     map[engine.Dot]Cell{},
+		direction: direction,
+		length:    length,
+	}
+
+	heads := 0
+
+	for y, row := range rows {
+		symbols := []rune(row)
+		if len(symbols) != width {
+			return nil, ErrInvalidSnapshot("rows have different width")
+		}
+		for x, symbol := range symbols {
+			dot := engine.Dot{
+				X: uint8(x),
+				Y: uint8(y),
+			}
+			if symbol == symbolSelf {
+				v.head = dot
+				heads++
+				continue
+			}
+			cell, ok := symbolCells[symbol]
+			if !ok {
+				return nil, ErrInvalidSnapshot("unknown symbol " + string(symbol))
+			}
+			if cell != CellEmpty {
+				v.cells[dot] = cell
+			}
+		}
+	}
+
+	if heads != 1 {
+		return nil, ErrInvalidSnapshot("snapshot must have one head of snake of bot")
+	}
+
+	return v, nil
+}
+
+// Head returns head of snake of bot
+func (v *View) Head() engine.Dot {
+	return v.head
+}
+
+// Direction returns movement direction of snake of bot
+func (v *View) Direction() engine.Direction {
+	return v.direction
+}
+
+// Length returns length of snake of bot
+func (v *View) Length() uint16 {
+	return v.length
+}
+
+// Cell returns content of passed dot
+func (v *View) Cell(dot engine.Dot) Cell {
+	return v.cells[dot]
+}
+
+// Passable returns true if snake can move to passed dot
+func (v *View) Passable(dot engine.Dot) bool {
+	cell := v.Cell(dot)
+	return cell == CellEmpty || cell == CellFood
+}
+
+// Navigate returns neighbor dot of passed dot in direction
+func (v *View) Navigate(dot engine.Dot, dir engine.Direction) engine.Dot {
+	next, err := v.area.Navigate(dot, dir, 1)
+	if err != nil {
+		return dot
+	}
+	return next
+}
+
+// Dots returns dots which contain passed cell
+func (v *View) Dots(cell Cell) []engine.Dot {
+	dots := make([]engine.Dot, 0)
+	for dot, c := range v.cells {
+		if c == cell {
+			dots = append(dots, dot)
+		}
+	}
+	return dots
+}
@@ -0,0 +1,78 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/profile"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+type obstacle struct{}
+
+type food struct{}
+
+func (food) NutritionalValue(dot engine.Dot) uint16 {
+	return 1
+}
+
+func Test_newView_ClassifiesObjects(t *testing.T) {
+	w, err := world.NewWorld(10, 10)
+	require.Nil(t, err)
+
+	s, err := snake.NewSnake(w, &profile.Profile{}, nil)
+	require.Nil(t, err)
+
+	free := make([]engine.Dot, 0, 2)
+	for x := uint8(0); x < 10 && len(free) < 2; x++ {
+		dot := engine.Dot{X: x, Y: 0}
+		if w.GetObjectByDot(dot) == nil {
+			free = append(free, dot)
+		}
+	}
+	require.Len(t, free, 2)
+	require.Nil(t, w.CreateObject(food{}, engine.Location{free[0]}))
+	require.Nil(t, w.CreateObject(obstacle{}, engine.Location{free[1]}))
+
+	view := newView(w, s)
+	location := s.GetLocation()
+
+	require.Equal(t, location[0], view.Head())
+	require.Equal(t, s.GetLength(), view.Length())
+	require.Equal(t, CellFood, view.Cell(free[0]))
+	require.Equal(t, CellObstacle, view.Cell(free[1]))
+	for _, dot := range location[1:] {
+		require.Equal(t, CellObstacle, view.Cell(dot), "body of own snake is obstacle")
+	}
+}
+
+func Test_NewSnapshot(t *testing.T) {
+	view, err := NewSnapshot([]string{
+		"#*.",
+		".@X",
+	}, engine.DirectionEast, 3)
+	require.Nil(t, err)
+
+	require.Equal(t, engine.Dot{X: 1, Y: 1}, view.Head())
+	require.Equal(t, engine.DirectionEast, view.Direction())
+	require.Equal(t, CellObstacle, view.Cell(engine.Dot{X: 0, Y: 0}))
+	require.Equal(t, CellFood, view.Cell(engine.Dot{X: 1, Y: 0}))
+	require.Equal(t, CellHead, view.Cell(engine.Dot{X: 2, Y: 1}))
+	require.Equal(t, CellEmpty, view.Cell(engine.Dot{X: 1, Y: 1}))
+	require.Equal(t, []engine.Dot{{X: 1, Y: 0}}, view.Dots(CellFood))
+	require.Equal(t, engine.Dot{X: 0, Y: 1}, view.Navigate(engine.Dot{X: 2, Y: 1}, engine.DirectionEast))
+
+	for _, rows := range [][]string{
+		{},
+		{"..", "."},
+		{"..", ".."},
+		{"@@"},
+		{"@?"},
+	} {
+		_, err := NewSnapshot(rows, engine.DirectionNorth, 3)
+		require.NotNil(t, err)
+	}
+}
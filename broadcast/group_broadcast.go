@@ -11,8 +11,6 @@ const (
 	broadcastSendTimeout        = time.Millisecond * 100
 )
 
-type BroadcastMessage string
-
 type GroupBroadcast struct {
 	chStop chan struct{}
 	chMain chan BroadcastMessage
@@ -0,0 +1,120 @@
+package broadcast
+
+import (
+	"time"
+)
+
+// MessageKind is kind of broadcast message
+type MessageKind uint8
+
+const (
+	// MessageKindSystem is notice of server
+	MessageKindSystem MessageKind = iota
+	// MessageKindChat is text message of player
+	MessageKindChat
+	// MessageKindEmote is emote of player
+	MessageKindEmote
+)
+
+var messageKindLabels = map[MessageKind]string{
+	MessageKindSystem: "system",
+	MessageKindChat:   "chat",
+	MessageKindEmote:  "emote",
+}
+
+func (k MessageKind) String() string {
+	if label, ok := messageKindLabels[k]; ok {
+		return label
+	}
+	return "unknown"
+}
+
+var messageKindJSONs = map[MessageKind][]byte{
+	MessageKindSystem: []byte(`"system"`),
+	MessageKindChat:   []byte(`"chat"`),
+	MessageKindEmote:  []byte(`"emote"`),
+}
+
+func (k MessageKind) MarshalJSON() ([]byte, error) {
+	if json, ok := messageKindJSONs[k]; ok {
+		return json, nil
+	}
+	return []byte(`"unknown"`), nil
+}
+
+// BroadcastMessage is message sent to all listeners of group broadcast
+type BroadcastMessage struct {
+	Kind MessageKind `json:"kind"`
+	// Sender is player ID of sender. System messages have no sender
+	Sender   string    `json:"sender,omitempty"`
+	Nickname string    `json:"nickname,omitempty"`
+	Time     time.Time `json:"time"`
+	Text     string    `json:"text,omitempty"`
+	Emote    string    `json:"emote,omitempty"`
+	// Recipient is player ID of recipient of private message or team name of team message
+	Recipient string `json:"recipient,omitempty"`
+
+	// private means that only sender and recipient get message
+	private bool
+}
+
+// NewSystemMessage creates notice of server
+func NewSystemMessage(text string) BroadcastMessage {
+	return BroadcastMessage{
+		Kind: MessageKindSystem,
+		Time: time.Now(),
+		Text: text,
+	}
+}
+
+// NewChatMessage creates text message of player
+func NewChatMessage(sender, nickname, text string) BroadcastMessage {
+	return BroadcastMessage{
+		Kind:     MessageKindChat,
+		Sender:   sender,
+		Nickname: nickname,
+		Time:     time.Now(),
+		Text:     text,
+	}
+}
+
+// NewPrivateMessage creates text message of player which is delivered only to recipient
+func NewPrivateMessage(sender, nickname, recipient, text string) BroadcastMessage {
+	message := NewChatMessage(sender, nickname, text)
+	message.Recipient = recipient
+	message.private = true
+	return message
+}
+
+// NewEmoteMessage creates emote of player
+func NewEmoteMessage(sender, nickname, emote string) BroadcastMessage {
+	return BroadcastMessage{
+		Kind:     MessageKindEmote,
+		Sender:   sender,
+		Nickname: nickname,
+		Time:     time.Now(),
+		Emote:    emote,
+	}
+}
+
+// VisibleTo returns true if player with passed ID can get message
+func (m BroadcastMessage) VisibleTo(player string) bool {
+	if !m.private {
+		return true
+	}
+	return player != "" && (player == m.Recipient || player == m.Sender)
+}
+
+// String returns message as plain text for clients which do not support structured messages
+func (m BroadcastMessage) String() string {
+	switch m.Kind {
+	case MessageKindChat:
+		if m.private {
+			return m.Nickname + " (private): " + m.Text
+		}
+		return m.Nickname + ": " + m.Text
+	case MessageKindEmote:
+		return m.Nickname + ": :" + m.Emote + ":"
+	}
+	return m.Text
+}
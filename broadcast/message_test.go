@@ -0,0 +1,43 @@
+package broadcast
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BroadcastMessage_VisibleTo(t *testing.T) {
+	require.True(t, NewSystemMessage("hello").VisibleTo(""))
+	require.True(t, NewChatMessage("a", "Ivan", "hello").VisibleTo("b"))
+
+	message := NewPrivateMessage("a", "Ivan", "b", "hello")
+	require.True(t, message.VisibleTo("a"))
+	require.True(t, message.VisibleTo("b"))
+	require.False(t, message.VisibleTo("c"))
+	require.False(t, message.VisibleTo(""), "spectators do not get private messages")
+}
+
+func Test_BroadcastMessage_String(t *testing.T) {
+	require.Equal(t, "Ivan joined your game group", NewSystemMessage("Ivan joined your game group").String())
+	require.Equal(t, "Ivan: hello", NewChatMessage("a", "Ivan", "hello").String())
+	require.Equal(t, "Ivan (private): hello", NewPrivateMessage("a", "Ivan", "b", "hello").String())
+	require.Equal(t, "Ivan: :gg:", NewEmoteMessage("a", "Ivan", "gg").String())
+}
+
+func Test_BroadcastMessage_MarshalJSON(t *testing.T) {
+	message := NewEmoteMessage("a", "Ivan", "gg")
+
+	data, err := json.Marshal(message)
+	require.Nil(t, err)
+
+	var decoded map[string]interface{}
+	require.Nil(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, "emote", decoded["kind"])
+	require.Equal(t, "a", decoded["sender"])
+	require.Equal(t, "Ivan", decoded["nickname"])
+	require.Equal(t, "gg", decoded["emote"])
+	require.NotContains(t, decoded, "text")
+	require.NotContains(t, decoded, "recipient")
+	require.Contains(t, decoded, "time")
+}
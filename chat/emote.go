@@ -0,0 +1,25 @@
+package chat
+
+// emotes contains codes of emotes which players can send
+var emotes = map[string]struct{}{
+	"smile":  {},
+	"laugh":  {},
+	"wink":   {},
+	"sad":    {},
+	"angry":  {},
+	"wave":   {},
+	"thumbs": {},
+	"gg":     {},
+}
+
+// ErrUnknownEmote is returned for emote code which is not supported
+const ErrUnknownEmote = ErrRejected("unknown emote")
+
+// ErrNoRecipient is returned for private message without recipient
+const ErrNoRecipient = ErrRejected("no recipient")
+
+// IsEmote returns true if passed code is code of supported emote
+func IsEmote(code string) bool {
+	_, ok := emotes[code]
+	return ok
+}
@@ -0,0 +1,263 @@
+package chat
+
+import (
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Maximal length of chat message in characters
+const maxMessageLength = 512
+
+// Defaults of chat moderation
+const (
+	defaultRate            = 5
+	defaultInterval        = time.Second * 10
+	defaultMaxLength       = 100
+	defaultDuplicateWindow = time.Second * 30
+)
+
+// Config contains settings of chat moderation
+type Config struct {
+	// Rate is count of messages which player can send per Interval. Zero disables rate limiting
+	Rate     int
+	Interval time.Duration
+	// MaxLength is maximal length of message in characters. Zero means maximal allowed length
+	MaxLength int
+	// Blocklist contains words which are masked in messages. Words are matched ignoring case
+	Blocklist []string
+	// DuplicateWindow is period during which player cannot repeat the previous message. Zero disables the check
+	DuplicateWindow time.Duration
+}
+
+// DefaultConfig returns settings of moderation with rate limit, length limit and duplicate suppression
+func DefaultConfig() Config {
+	return Config{
+		Rate:            defaultRate,
+		Interval:        defaultInterval,
+		MaxLength:       defaultMaxLength,
+		DuplicateWindow: defaultDuplicateWindow,
+	}
+}
+
+type ErrInvalidConfig string
+
+func (e ErrInvalidConfig) Error() string {
+	return "invalid chat config: " + string(e)
+}
+
+// Validate checks settings of chat moderation
+func (c Config) Validate() error {
+	if c.Rate < 0 {
+		return ErrInvalidConfig("negative rate")
+	}
+	if c.Rate > 0 && c.Interval <= 0 {
+		return ErrInvalidConfig("rate requires positive interval")
+	}
+	if c.MaxLength < 0 || c.MaxLength > maxMessageLength {
+		return ErrInvalidConfig("max length out of range")
+	}
+	if c.DuplicateWindow < 0 {
+		return ErrInvalidConfig("negative duplicate window")
+	}
+	for _, word := range c.Blocklist {
+		if strings.TrimSpace(word) == "" {
+			return ErrInvalidConfig("empty blocked word")
+		}
+	}
+	return nil
+}
+
+func (c Config) maxLength() int {
+	if c.MaxLength == 0 {
+		return maxMessageLength
+	}
+	return c.MaxLength
+}
+
+// ErrRejected is reason why message of player is not delivered
+type ErrRejected string
+
+func (e ErrRejected) Error() string {
+	return "message rejected: " + string(e)
+}
+
+const (
+	ErrEmptyMessage = ErrRejected("empty message")
+	ErrTooLong      = ErrRejected("message is too long")
+	ErrRateLimited  = ErrRejected("too many messages")
+	ErrDuplicate    = ErrRejected("repeated message")
+	ErrMuted        = ErrRejected("player is muted")
+)
+
+// sender contains recent messages of player
+type sender struct {
+	// times contains moments of messages sent during the last rate interval
+	times    []time.Time
+	last     string
+	lastTime time.Time
+}
+
+// Moderator checks messages of players before they are broadcast
+type Moderator struct {
+	config    Config
+	blocklist map[string]struct{}
+
+	senders map[string]*sender
+	// mutes contains moments when players get unmuted. Zero moment means that player is muted until unmute
+	mutes map[string]time.Time
+	mux   *sync.Mutex
+}
+
+func NewModerator(config Config) (*Moderator, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	blocklist := make(map[string]struct{}, len(config.Blocklist))
+	for _, word := range config.Blocklist {
+		blocklist[strings.ToLower(strings.TrimSpace(word))] = struct{}{}
+	}
+
+	return &Moderator{
+		config:    config,
+		blocklist: blocklist,
+		senders:   map[string]*sender{},
+		mutes:     map[string]time.Time{},
+		mux:       &sync.Mutex{},
+	}, nil
+}
+
+// Moderate checks message of player sent at passed moment. It returns text with masked blocked words or error if
+// message is rejected
+func (m *Moderator) Moderate(player, text string, now time.Time) (string, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if m.unsafeMuted(player, now) {
+		return "", ErrMuted
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", ErrEmptyMessage
+	}
+	if utf8.RuneCountInString(text) > m.config.maxLength() {
+		return "", ErrTooLong
+	}
+
+	s, ok := m.senders[player]
+	if !ok {
+		s = &sender{}
+		m.senders[player] = s
+	}
+
+	if m.config.DuplicateWindow > 0 && strings.EqualFold(s.last, text) &&
+		now.Sub(s.lastTime) < m.config.DuplicateWindow {
+		return "", ErrDuplicate
+	}
+
+	if m.config.Rate > 0 {
+		since := now.Add(-m.config.Interval)
+		recent := s.times[:0]
+		for _, t := range s.times {
+			if t.After(since) {
+				recent = append(recent, t)
+			}
+		}
+		s.times = recent
+
+		if len(s.times) >= m.config.Rate {
+			return "", ErrRateLimited
+		}
+		s.times = append(s.times, now)
+	}
+
+	s.last = text
+	s.lastTime = now
+
+	return m.filter(text), nil
+}
+
+// filter masks blocked words in text
+func (m *Moderator) filter(text string) string {
+	if len(m.blocklist) == 0 {
+		return text
+	}
+
+	runes := []rune(text)
+	isWordRune := func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+
+	for start := 0; start < len(runes); {
+		if !isWordRune(runes[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(runes) && isWordRune(runes[end]) {
+			end++
+		}
+		if _, ok := m.blocklist[strings.ToLower(string(runes[start:end]))]; ok {
+			for i := start; i < end; i++ {
+				runes[i] = '*'
+			}
+		}
+		start = end
+	}
+
+	return string(runes)
+}
+
+// Mute forbids player to send messages for passed duration. Zero duration mutes player until unmute
+func (m *Moderator) Mute(player string, duration time.Duration, now time.Time) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if duration > 0 {
+		m.mutes[player] = now.Add(duration)
+	} else {
+		m.mutes[player] = time.Time{}
+	}
+}
+
+// Unmute lets player send messages. It returns false if player was not muted
+func (m *Moderator) Unmute(player string) bool {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if _, ok := m.mutes[player]; !ok {
+		return false
+	}
+	delete(m.mutes, player)
+	return true
+}
+
+// Muted returns true if player cannot send messages at passed moment
+func (m *Moderator) Muted(player string, now time.Time) bool {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	return m.unsafeMuted(player, now)
+}
+
+func (m *Moderator) unsafeMuted(player string, now time.Time) bool {
+	until, ok := m.mutes[player]
+	if !ok {
+		return false
+	}
+	if !until.IsZero() && !now.Before(until) {
+		delete(m.mutes, player)
+		return false
+	}
+	return true
+}
+
+// Forget drops recent messages of player who left the game. Mutes are kept
+func (m *Moderator) Forget(player string) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	delete(m.senders, player)
+}
@@ -0,0 +1,124 @@
+package chat
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Config_Validate(t *testing.T) {
+	require.Nil(t, DefaultConfig().Validate())
+	require.Nil(t, Config{}.Validate(), "no moderation")
+
+	for _, config := range []Config{
+		{Rate: -1},
+		{Rate: 1},
+		{MaxLength: -1},
+		{MaxLength: maxMessageLength + 1},
+		{DuplicateWindow: -time.Second},
+		{Blocklist: []string{"bad", " "}},
+	} {
+		err := config.Validate()
+		require.NotNil(t, err)
+		require.IsType(t, ErrInvalidConfig(""), err)
+	}
+}
+
+func Test_Moderator_Moderate_LimitsRate(t *testing.T) {
+	m, err := NewModerator(Config{
+		Rate:     2,
+		Interval: time.Second,
+	})
+	require.Nil(t, err)
+
+	now := time.Now()
+
+	_, err = m.Moderate("player", "one", now)
+	require.Nil(t, err)
+	_, err = m.Moderate("player", "two", now.Add(time.Millisecond*100))
+	require.Nil(t, err)
+	_, err = m.Moderate("player", "three", now.Add(time.Millisecond*200))
+	require.Equal(t, ErrRateLimited, err)
+
+	_, err = m.Moderate("other", "one", now.Add(time.Millisecond*200))
+	require.Nil(t, err, "rate is counted per player")
+
+	_, err = m.Moderate("player", "four", now.Add(time.Second))
+	require.Nil(t, err, "the first message left interval")
+}
+
+func Test_Moderator_Moderate_LimitsLength(t *testing.T) {
+	m, err := NewModerator(Config{
+		MaxLength: 5,
+	})
+	require.Nil(t, err)
+
+	now := time.Now()
+
+	text, err := m.Moderate("player", "  пять  ", now)
+	require.Nil(t, err, "length is counted in characters")
+	require.Equal(t, "пять", text)
+
+	_, err = m.Moderate("player", "too long", now)
+	require.Equal(t, ErrTooLong, err)
+
+	_, err = m.Moderate("player", "   ", now)
+	require.Equal(t, ErrEmptyMessage, err)
+
+	m, err = NewModerator(Config{})
+	require.Nil(t, err)
+	_, err = m.Moderate("player", strings.Repeat("a", maxMessageLength+1), now)
+	require.Equal(t, ErrTooLong, err)
+}
+
+func Test_Moderator_Moderate_SuppressesDuplicates(t *testing.T) {
+	m, err := NewModerator(Config{
+		DuplicateWindow: time.Second * 10,
+	})
+	require.Nil(t, err)
+
+	now := time.Now()
+
+	_, err = m.Moderate("player", "hello", now)
+	require.Nil(t, err)
+	_, err = m.Moderate("player", "HELLO", now.Add(time.Second))
+	require.Equal(t, ErrDuplicate, err)
+	_, err = m.Moderate("other", "hello", now.Add(time.Second))
+	require.Nil(t, err)
+	_, err = m.Moderate("player", "hello", now.Add(time.Second*10))
+	require.Nil(t, err, "window is passed")
+}
+
+func Test_Moderator_Moderate_MasksBlockedWords(t *testing.T) {
+	m, err := NewModerator(Config{
+		Blocklist: []string{"bad", "Злой"},
+	})
+	require.Nil(t, err)
+
+	text, err := m.Moderate("player", "Bad snake, badge is ok, злой!", time.Now())
+	require.Nil(t, err)
+	require.Equal(t, "*** snake, badge is ok, ****!", text)
+}
+
+func Test_Moderator_Mute(t *testing.T) {
+	m, err := NewModerator(Config{})
+	require.Nil(t, err)
+
+	now := time.Now()
+
+	m.Mute("player", time.Minute, now)
+	require.True(t, m.Muted("player", now))
+	_, err = m.Moderate("player", "hello", now)
+	require.Equal(t, ErrMuted, err)
+	require.False(t, m.Muted("player", now.Add(time.Minute)), "mute is expired")
+
+	m.Mute("player", 0, now)
+	require.True(t, m.Muted("player", now.Add(time.Hour*24)), "muted until unmute")
+	require.True(t, m.Unmute("player"))
+	require.False(t, m.Unmute("player"))
+
+	_, err = m.Moderate("player", "hello", now)
+	require.Nil(t, err)
+}
@@ -0,0 +1,39 @@
+package connections
+
+import (
+	"errors"
+)
+
+// ChatFormat is format of broadcast messages sent to client
+type ChatFormat uint8
+
+const (
+	// ChatFormatText sends broadcast messages as plain strings. It is default format for old clients
+	ChatFormatText ChatFormat = iota
+	// ChatFormatStructured sends broadcast messages as objects with kind, sender and timestamp
+	ChatFormatStructured
+)
+
+var chatFormatLabels = map[ChatFormat]string{
+	ChatFormatText:       "text",
+	ChatFormatStructured: "structured",
+}
+
+func (f ChatFormat) String() string {
+	if label, ok := chatFormatLabels[f]; ok {
+		return label
+	}
+	return "unknown"
+}
+
+var ErrUnknownChatFormat = errors.New("unknown chat format")
+
+// ParseChatFormat returns chat format by label
+func ParseChatFormat(label string) (ChatFormat, error) {
+	for format, formatLabel := range chatFormatLabels {
+		if formatLabel == label {
+			return format, nil
+		}
+	}
+	return 0, ErrUnknownChatFormat
+}
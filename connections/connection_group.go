@@ -4,40 +4,82 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/ivan1993spb/snake-server/bot"
 	"github.com/ivan1993spb/snake-server/broadcast"
+	"github.com/ivan1993spb/snake-server/chat"
 	"github.com/ivan1993spb/snake-server/game"
+	"github.com/ivan1993spb/snake-server/objects/registry"
+	"github.com/ivan1993spb/snake-server/teams"
 )
 
 type ConnectionGroup struct {
 	limit   int
 	counter int
-	mutex   *sync.RWMutex
+	// Spectators are counted apart from players
+	spectatorLimit   int
+	spectatorCounter int
+	mutex            *sync.RWMutex
 
 	logger logrus.FieldLogger
 
 	game      *game.Game
 	broadcast *broadcast.GroupBroadcast
+	// teamBroadcasts contains team chats if game has teams
+	teamBroadcasts map[*teams.Team]*broadcast.GroupBroadcast
+	// moderator checks messages of players in group and team chats
+	moderator *chat.Moderator
+	// bots keep count of players not less than target of game
+	bots *bot.Pool
 
 	stop chan struct{}
 }
 
-func NewConnectionGroup(logger logrus.FieldLogger, connectionLimit int, width, height uint8) (*ConnectionGroup, error) {
-	g, err := game.NewGame(logger, width, height)
+func NewConnectionGroup(logger logrus.FieldLogger, connectionLimit, spectatorLimit int,
+	config game.Config) (*ConnectionGroup, error) {
+	if spectatorLimit < 0 {
+		return nil, errors.New("cannot create connection group: invalid spectator limit")
+	}
+
+	g, err := game.NewGame(logger, config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create connection group: %s", err)
+	}
+
+	moderator, err := chat.NewModerator(config.Chat)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create connection group: %s", err)
+	}
+
+	bots, err := bot.NewPool(logger, g.World(), g.Teams(), g, g.Respawn(), config.Bots)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create connection group: %s", err)
 	}
 
 	if connectionLimit > 0 {
+		bots.SetLimit(connectionLimit)
+
+		teamBroadcasts := map[*teams.Team]*broadcast.GroupBroadcast{}
+		if g.Teams() != nil {
+			for _, team := range g.Teams().List() {
+				teamBroadcasts[team] = broadcast.NewGroupBroadcast()
+			}
+		}
+
 		return &ConnectionGroup{
-			limit:     connectionLimit,
-			mutex:     &sync.RWMutex{},
-			game:      g,
-			broadcast: broadcast.NewGroupBroadcast(),
-			logger:    logger,
-			stop:      make(chan struct{}),
+			limit:          connectionLimit,
+			spectatorLimit: spectatorLimit,
+			mutex:          &sync.RWMutex{},
+			game:           g,
+			broadcast:      broadcast.NewGroupBroadcast(),
+			teamBroadcasts: teamBroadcasts,
+			moderator:      moderator,
+			bots:           bots,
+			logger:         logger,
+			stop:           make(chan struct{}),
 		}, nil
 	}
 
@@ -53,6 +95,7 @@ func (cg *ConnectionGroup) GetLimit() int {
 func (cg *ConnectionGroup) SetLimit(limit int) {
 	cg.mutex.Lock()
 	cg.limit = limit
+	cg.bots.SetLimit(limit)
 	cg.mutex.Unlock()
 }
 
@@ -62,6 +105,36 @@ func (cg *ConnectionGroup) GetCount() int {
 	return cg.counter
 }
 
+// GetBotCount returns count of bots playing in group
+func (cg *ConnectionGroup) GetBotCount() int {
+	return cg.bots.Count()
+}
+
+// GetSpectatorLimit returns limit of spectator connections
+func (cg *ConnectionGroup) GetSpectatorLimit() int {
+	cg.mutex.RLock()
+	defer cg.mutex.RUnlock()
+	return cg.spectatorLimit
+}
+
+// GetSpectatorCount returns count of spectator connections
+func (cg *ConnectionGroup) GetSpectatorCount() int {
+	cg.mutex.RLock()
+	defer cg.mutex.RUnlock()
+	return cg.spectatorCounter
+}
+
+// unsafeIsSpectatorsFull returns true if group has no places for spectators
+func (cg *ConnectionGroup) unsafeIsSpectatorsFull() bool {
+	return cg.spectatorCounter >= cg.spectatorLimit
+}
+
+func (cg *ConnectionGroup) IsSpectatorsFull() bool {
+	cg.mutex.RLock()
+	defer cg.mutex.RUnlock()
+	return cg.unsafeIsSpectatorsFull()
+}
+
 // unsafeIsFull returns true if group is full
 func (cg *ConnectionGroup) unsafeIsFull() bool {
 	return cg.counter == cg.limit
@@ -103,15 +176,95 @@ func (cg *ConnectionGroup) Handle(connectionWorker *ConnectionWorker) *ErrRunCon
 		}
 	}
 	cg.counter += 1
+	cg.bots.Balance(cg.counter)
+	cg.mutex.Unlock()
+
+	spectator := false
+
+	var team *teams.Team
+
+	defer func() {
+		cg.mutex.Lock()
+		if spectator {
+			cg.spectatorCounter -= 1
+		} else {
+			cg.counter -= 1
+			cg.bots.Balance(cg.counter)
+			if team != nil {
+				// Spectator has left team when it was moved to spectators
+				cg.game.Teams().Leave(team)
+			}
+		}
+		cg.mutex.Unlock()
+	}()
+
+	if gameTeams := cg.game.Teams(); gameTeams != nil {
+		var err error
+		if team, err = gameTeams.Join(connectionWorker.team); err != nil {
+			return &ErrRunConnection{
+				Err: err,
+			}
+		}
+	}
+
+	// Idle player is moved to spectators to free place of player
+	toSpectators := func() bool {
+		cg.mutex.Lock()
+		defer cg.mutex.Unlock()
+		if !cg.unsafeToSpectators(team) {
+			return false
+		}
+		spectator = true
+		return true
+	}
+
+	err := connectionWorker.Start(cg.stop, cg.game, cg.broadcast, team, cg.teamBroadcasts[team], cg.moderator,
+		toSpectators)
+	if err != nil {
+		return &ErrRunConnection{
+			Err: err,
+		}
+	}
+
+	return nil
+}
+
+// unsafeToSpectators moves connection of player to spectators. Place of player and place in team are freed. It
+// returns false if spectators limit is reached
+func (cg *ConnectionGroup) unsafeToSpectators(team *teams.Team) bool {
+	if cg.unsafeIsSpectatorsFull() {
+		return false
+	}
+	cg.counter -= 1
+	cg.spectatorCounter += 1
+	cg.bots.Balance(cg.counter)
+	if team != nil {
+		cg.game.Teams().Leave(team)
+	}
+	return true
+}
+
+var ErrSpectatorsLimitReached = errors.New("spectators limit reached")
+
+// HandleSpectator runs connection of spectator. Spectators do not take places of players
+func (cg *ConnectionGroup) HandleSpectator(connectionWorker *ConnectionWorker) *ErrRunConnection {
+	cg.mutex.Lock()
+	if cg.unsafeIsSpectatorsFull() {
+		cg.mutex.Unlock()
+		return &ErrRunConnection{
+			Err: ErrSpectatorsLimitReached,
+		}
+	}
+	cg.spectatorCounter += 1
 	cg.mutex.Unlock()
 
 	defer func() {
 		cg.mutex.Lock()
-		cg.counter -= 1
+		cg.spectatorCounter -= 1
 		cg.mutex.Unlock()
 	}()
 
-	if err := connectionWorker.Start(cg.stop, cg.game, cg.broadcast); err != nil {
+	if err := connectionWorker.Spectate(cg.stop, cg.game, cg.broadcast); err != nil {
 		return &ErrRunConnection{
 			Err: err,
 		}
@@ -122,7 +275,11 @@ func (cg *ConnectionGroup) Handle(connectionWorker *ConnectionWorker) *ErrRunCon
 
 func (cg *ConnectionGroup) Start() {
 	cg.broadcast.Start(cg.stop)
+	for _, teamBroadcast := range cg.teamBroadcasts {
+		teamBroadcast.Start(cg.stop)
+	}
 	cg.game.Start(cg.stop)
+	cg.bots.Start(cg.stop)
 }
 
 func (cg *ConnectionGroup) Stop() {
@@ -136,3 +293,45 @@ func (cg *ConnectionGroup) GetWorldWidth() uint8 {
 func (cg *ConnectionGroup) GetWorldHeight() uint8 {
 	return cg.game.World().Height()
 }
+
+func (cg *ConnectionGroup) GetObjects() []string {
+	return cg.game.Objects()
+}
+
+// GetTeams returns names of teams or nil if game has no teams
+func (cg *ConnectionGroup) GetTeams() []string {
+	if gameTeams := cg.game.Teams(); gameTeams != nil {
+		return gameTeams.Names()
+	}
+	return nil
+}
+
+// MutePlayer forbids player to send chat messages for passed duration. Zero duration mutes player until unmute
+func (cg *ConnectionGroup) MutePlayer(player string, duration time.Duration) {
+	cg.moderator.Mute(player, duration, time.Now())
+}
+
+// UnmutePlayer lets player send chat messages. It returns false if player was not muted
+func (cg *ConnectionGroup) UnmutePlayer(player string) bool {
+	return cg.moderator.Unmute(player)
+}
+
+// GetScoreboard returns statistics of players in game
+func (cg *ConnectionGroup) GetScoreboard() []game.ScoreboardRow {
+	return cg.game.Scoreboard()
+}
+
+// GetSnapshot returns snapshots of objects on map which can be restored in new game
+func (cg *ConnectionGroup) GetSnapshot() ([]*registry.Snapshot, error) {
+	return cg.game.Snapshot()
+}
+
+// GetLobby returns true if game has round lifecycle
+func (cg *ConnectionGroup) GetLobby() bool {
+	return cg.game.Lifecycle()
+}
+
+// GetMode returns label of game mode
+func (cg *ConnectionGroup) GetMode() string {
+	return cg.game.Mode()
+}
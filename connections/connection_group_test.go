@@ -0,0 +1,41 @@
+package connections
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/game"
+)
+
+func Test_ConnectionGroup_unsafeToSpectators_FreesTeamPlace(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	cg, err := NewConnectionGroup(logger, 2, 1, game.Config{
+		Width:   20,
+		Height:  20,
+		Objects: []string{},
+		Teams:   2,
+	})
+	require.Nil(t, err)
+
+	gameTeams := cg.game.Teams()
+	team, err := gameTeams.Join("red")
+	require.Nil(t, err)
+	cg.counter = 1
+
+	require.True(t, cg.unsafeToSpectators(team))
+	require.Equal(t, 0, team.Members(), "spectator does not keep place in team")
+	require.Equal(t, 0, cg.GetCount())
+	require.Equal(t, 1, cg.GetSpectatorCount())
+
+	// Spectators limit is reached
+	other, err := gameTeams.Join("red")
+	require.Nil(t, err)
+	cg.counter = 1
+
+	require.False(t, cg.unsafeToSpectators(other))
+	require.Equal(t, 1, other.Members())
+	require.Equal(t, 1, cg.GetCount())
+}
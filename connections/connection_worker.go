@@ -1,7 +1,10 @@
 package connections
 
 import (
+	"fmt"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -9,8 +12,12 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/ivan1993spb/snake-server/broadcast"
+	"github.com/ivan1993spb/snake-server/chat"
 	"github.com/ivan1993spb/snake-server/game"
 	"github.com/ivan1993spb/snake-server/player"
+	"github.com/ivan1993spb/snake-server/profile"
+	"github.com/ivan1993spb/snake-server/sessions"
+	"github.com/ivan1993spb/snake-server/teams"
 )
 
 const (
@@ -25,11 +32,36 @@ const (
 	chanSnakeCommandsBuffer     = 32
 
 	sendInputMessageTimeout = time.Millisecond * 50
+
+	// Interval of checking whether player is idle
+	idleCheckInterval = time.Second
+	// Timeout of sending close message to idle player
+	closeMessageTimeout = time.Second
 )
 
+// Reason of actions applied to idle players
+const idleReason = "idle"
+
+// Payload of ready input message which marks player as not ready
+const inputPayloadNotReady = "false"
+
+// Separator of recipient and text in payload of private input message
+const privateMessageSeparator = " "
+
 type ConnectionWorker struct {
-	conn   *websocket.Conn
-	logger logrus.FieldLogger
+	// lastInput is unix time in nanoseconds of the last input message. It is accessed atomically
+	lastInput int64
+
+	// id is stable player identifier of session. It identifies player in game lobby
+	id      string
+	conn    *websocket.Conn
+	logger  logrus.FieldLogger
+	session *sessions.Session
+	profile *profile.Profile
+	// team is name of team requested by player. Empty team means any team
+	team string
+	// chatFormat is format of broadcast messages sent to client
+	chatFormat ChatFormat
 
 	chsInput    []chan InputMessage
 	chsInputMux *sync.RWMutex
@@ -37,10 +69,27 @@ type ConnectionWorker struct {
 	flagStarted bool
 }
 
-func NewConnectionWorker(conn *websocket.Conn, logger logrus.FieldLogger) *ConnectionWorker {
+// NewSpectatorWorker creates connection worker of spectator which has no session and never gets snake
+func NewSpectatorWorker(conn *websocket.Conn, logger logrus.FieldLogger, chatFormat ChatFormat) *ConnectionWorker {
 	return &ConnectionWorker{
 		conn:        conn,
 		logger:      logger,
+		chatFormat:  chatFormat,
+		chsInput:    make([]chan InputMessage, 0),
+		chsInputMux: &sync.RWMutex{},
+	}
+}
+
+func NewConnectionWorker(conn *websocket.Conn, logger logrus.FieldLogger, session *sessions.Session,
+	team string, chatFormat ChatFormat) *ConnectionWorker {
+	return &ConnectionWorker{
+		id:          session.PlayerID(),
+		conn:        conn,
+		logger:      logger,
+		session:     session,
+		profile:     session.Profile(),
+		team:        team,
+		chatFormat:  chatFormat,
 		chsInput:    make([]chan InputMessage, 0),
 		chsInputMux: &sync.RWMutex{},
 	}
@@ -52,29 +101,113 @@ func (e ErrStartConnectionWorker) Error() string {
 	return "error start connection worker: " + string(e)
 }
 
-func (cw *ConnectionWorker) Start(stop <-chan struct{}, game *game.Game, broadcast *broadcast.GroupBroadcast) error {
+// Start runs connection worker. Team and team broadcast are nil if game has no teams. Moderator checks chat messages
+// of player. Function toSpectators moves connection of idle player to spectators and returns false if there are no
+// free places of spectators
+func (cw *ConnectionWorker) Start(stop <-chan struct{}, game *game.Game, groupBroadcast *broadcast.GroupBroadcast,
+	team *teams.Team, teamBroadcast *broadcast.GroupBroadcast, moderator *chat.Moderator,
+	toSpectators func() bool) error {
 	if cw.flagStarted {
 		return ErrStartConnectionWorker("connection worker already started")
 	}
 
 	cw.flagStarted = true
+	cw.touch(time.Now())
 
-	broadcast.BroadcastMessage("user joined your game group")
+	groupBroadcast.BroadcastMessage(broadcast.NewSystemMessage(fmt.Sprintf("%s joined your game group", cw.profile.Nickname)))
 
 	// Input
 	chInputBytes, chStop := cw.read()
 	chInputMessages := cw.decode(chInputBytes, chStop)
 	cw.broadcastInputMessage(chInputMessages, chStop)
 	chCommands := cw.listenSnakeCommands(chStop, cw.input(chStop, chanInputMessagesBuffer))
-	cw.listenPlayerBroadcasts(chStop, cw.input(chStop, chanInputMessagesBuffer), broadcast)
+	chChat := cw.listenPlayerBroadcasts(chStop, cw.input(chStop, chanInputMessagesBuffer), groupBroadcast, nil,
+		moderator)
+	defer moderator.Forget(cw.id)
+
+	if game.Lifecycle() {
+		game.JoinLobby(cw.id)
+		defer game.LeaveLobby(cw.id)
+		cw.listenReady(chStop, cw.input(chStop, chanInputMessagesBuffer), game)
+	}
 
-	p := player.NewPlayer(cw.logger, game.World())
+	p := player.NewPlayer(cw.logger, game.World(), cw.profile, team, game, game.Respawn(), game.Parking())
 
 	// Output
 	chPlayer := p.Start(chStop, chCommands)
 	chGame := game.ListenEvents(chStop, chanEventsBuffer)
-	chBroadcast := broadcast.ListenMessages(chStop, chanBroadcastBuffer)
-	chOutputBytes := cw.encode(chStop, cw.listenBroadcast(chStop, chBroadcast), cw.listenPlayer(chStop, chPlayer), cw.listenGame(chStop, chGame))
+	chBroadcast := groupBroadcast.ListenMessages(chStop, chanBroadcastBuffer)
+	chsOutput := []<-chan OutputMessage{
+		cw.sendSession(),
+		cw.listenBroadcast(chStop, chBroadcast, OutputMessageTypeBroadcast),
+		cw.listenPlayer(chStop, chPlayer),
+		cw.listenGame(chStop, chGame),
+		chChat,
+	}
+
+	if teamBroadcast != nil {
+		chTeamChat := cw.listenPlayerBroadcasts(chStop, cw.input(chStop, chanInputMessagesBuffer), teamBroadcast, team,
+			moderator)
+		chTeamBroadcast := teamBroadcast.ListenMessages(chStop, chanBroadcastBuffer)
+		chsOutput = append(chsOutput, chTeamChat, cw.listenBroadcast(chStop, chTeamBroadcast, OutputMessageTypeTeam))
+	}
+
+	var chIdle <-chan struct{}
+	if game.Idle().Enabled() {
+		var chIdleOutput <-chan OutputMessage
+		chIdleOutput, chIdle = cw.watchIdle(chStop, game.Idle())
+		chsOutput = append(chsOutput, chIdleOutput)
+	}
+
+	chOutputBytes := cw.encode(chStop, chsOutput...)
+	cw.write(chOutputBytes, chStop)
+
+loop:
+	for {
+		select {
+		case <-chStop:
+			// On connection error
+			break loop
+		case <-stop:
+			// External stop
+			break loop
+		case <-chIdle:
+			chIdle = nil
+			cw.applyIdleAction(game, p, toSpectators)
+		}
+	}
+
+	groupBroadcast.BroadcastMessage(broadcast.NewSystemMessage(fmt.Sprintf("%s left your game group", cw.profile.Nickname)))
+
+	cw.stopInputs()
+
+	return nil
+}
+
+// Spectate runs connection worker of spectator. Spectator receives playground, game events and group broadcasts
+func (cw *ConnectionWorker) Spectate(stop <-chan struct{}, game *game.Game,
+	groupBroadcast *broadcast.GroupBroadcast) error {
+	if cw.flagStarted {
+		return ErrStartConnectionWorker("connection worker already started")
+	}
+
+	cw.flagStarted = true
+
+	// Input messages of spectator are ignored
+	chInputBytes, chStop := cw.read()
+	chInputMessages := cw.decode(chInputBytes, chStop)
+	cw.broadcastInputMessage(chInputMessages, chStop)
+
+	// Output
+	chPlayer := player.Spectate(chStop, game.World())
+	chGame := game.ListenEvents(chStop, chanEventsBuffer)
+	chBroadcast := groupBroadcast.ListenMessages(chStop, chanBroadcastBuffer)
+
+	chOutputBytes := cw.encode(chStop,
+		cw.listenBroadcast(chStop, chBroadcast, OutputMessageTypeBroadcast),
+		cw.listenPlayer(chStop, chPlayer),
+		cw.listenGame(chStop, chGame),
+	)
 	cw.write(chOutputBytes, chStop)
 
 	select {
@@ -84,10 +217,6 @@ func (cw *ConnectionWorker) Start(stop <-chan struct{}, game *game.Game, broadca
 		// External stop
 	}
 
-	broadcast.BroadcastMessage("user left your game group")
-
-	cw.stopInputs()
-
 	return nil
 }
 
@@ -160,6 +289,7 @@ func (cw *ConnectionWorker) broadcastInputMessage(chin <-chan InputMessage, stop
 		for {
 			select {
 			case inputMessage := <-chin:
+				cw.touch(time.Now())
 				cw.chsInputMux.RLock()
 				for _, ch := range cw.chsInput {
 					select {
@@ -295,6 +425,101 @@ func (cw *ConnectionWorker) encode(stop <-chan struct{}, chins ...<-chan OutputM
 	return chout
 }
 
+// touch remembers passed time as time of the last input message
+func (cw *ConnectionWorker) touch(now time.Time) {
+	atomic.StoreInt64(&cw.lastInput, now.UnixNano())
+}
+
+// idleFor returns time passed since the last input message
+func (cw *ConnectionWorker) idleFor(now time.Time) time.Duration {
+	return now.Sub(time.Unix(0, atomic.LoadInt64(&cw.lastInput)))
+}
+
+// watchIdle warns player who sends no input. Returned second channel is closed when player is idle for idle timeout
+func (cw *ConnectionWorker) watchIdle(stop <-chan struct{}, idle game.Idle) (<-chan OutputMessage, <-chan struct{}) {
+	chout := make(chan OutputMessage, chanOutputMessageBuffer)
+	chIdle := make(chan struct{})
+
+	go func() {
+		defer close(chout)
+
+		ticker := time.NewTicker(idleCheckInterval)
+		defer ticker.Stop()
+
+		warned := false
+
+		for {
+			select {
+			case now := <-ticker.C:
+				idleFor := cw.idleFor(now)
+				if idleFor >= idle.Timeout {
+					close(chIdle)
+					return
+				}
+				if idleFor < idle.Timeout-idle.Warning {
+					warned = false
+					continue
+				}
+				if warned {
+					continue
+				}
+				warned = true
+
+				left := (idle.Timeout - idleFor).Round(time.Second)
+				outputMessage := OutputMessage{
+					Type:    OutputMessageTypePlayer,
+					Payload: player.NewMessageNotice(fmt.Sprintf("no input: %s in %s", idle.Action, left)),
+				}
+
+				select {
+				case chout <- outputMessage:
+				case <-stop:
+					return
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	return chout, chIdle
+}
+
+// applyIdleAction moves idle player to spectators or disconnects idle player. Idle player is disconnected if there
+// are no free places of spectators
+func (cw *ConnectionWorker) applyIdleAction(g *game.Game, p *player.Player, toSpectators func() bool) {
+	if g.Idle().Action == game.IdleActionSpectate && toSpectators() {
+		p.Leave(idleReason)
+		g.LeaveLobby(cw.id)
+		return
+	}
+	// Connection error follows closing of connection
+	cw.disconnect(idleReason)
+}
+
+// disconnect sends close message with passed reason and closes connection
+func (cw *ConnectionWorker) disconnect(reason string) {
+	message := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, reason)
+	deadline := time.Now().Add(closeMessageTimeout)
+	if err := cw.conn.WriteControl(websocket.CloseMessage, message, deadline); err != nil {
+		cw.logger.Errorln("write close message error:", err)
+	}
+	if err := cw.conn.Close(); err != nil {
+		cw.logger.Errorln("close connection error:", err)
+	}
+}
+
+// sendSession returns channel with single message containing session of player
+func (cw *ConnectionWorker) sendSession() <-chan OutputMessage {
+	chout := make(chan OutputMessage, 1)
+	chout <- OutputMessage{
+		Type:    OutputMessageTypeSession,
+		Payload: cw.session,
+	}
+	close(chout)
+	return chout
+}
+
 func (cw *ConnectionWorker) listenGame(stop <-chan struct{}, chin <-chan game.Event) <-chan OutputMessage {
 	chout := make(chan OutputMessage, chanOutputMessageBuffer)
 
@@ -351,7 +576,9 @@ func (cw *ConnectionWorker) listenPlayer(stop <-chan struct{}, chin <-chan playe
 	return chout
 }
 
-func (cw *ConnectionWorker) listenBroadcast(stop <-chan struct{}, chin <-chan broadcast.BroadcastMessage) <-chan OutputMessage {
+// listenBroadcast passes broadcast messages visible to player in chat format of client
+func (cw *ConnectionWorker) listenBroadcast(stop <-chan struct{}, chin <-chan broadcast.BroadcastMessage,
+	messageType OutputMessageType) <-chan OutputMessage {
 	chout := make(chan OutputMessage, chanOutputMessageBuffer)
 
 	go func() {
@@ -360,10 +587,17 @@ func (cw *ConnectionWorker) listenBroadcast(stop <-chan struct{}, chin <-chan br
 		for {
 			select {
 			case message := <-chin:
+				if !message.VisibleTo(cw.id) {
+					continue
+				}
+
 				outputMessage := OutputMessage{
-					Type:    OutputMessageTypeBroadcast,
+					Type:    messageType,
 					Payload: message,
 				}
+				if cw.chatFormat == ChatFormatText {
+					outputMessage.Payload = message.String()
+				}
 
 				select {
 				case chout <- outputMessage:
@@ -404,13 +638,14 @@ func (cw *ConnectionWorker) listenSnakeCommands(stop <-chan struct{}, chin <-cha
 	return chout
 }
 
-func (cw *ConnectionWorker) listenPlayerBroadcasts(stop <-chan struct{}, chin <-chan InputMessage, b *broadcast.GroupBroadcast) {
+// listenReady marks player as ready or not ready in game lobby
+func (cw *ConnectionWorker) listenReady(stop <-chan struct{}, chin <-chan InputMessage, game *game.Game) {
 	go func() {
 		for {
 			select {
 			case message := <-chin:
-				if message.Type == InputMessageTypeBroadcast {
-					b.BroadcastMessage(broadcast.BroadcastMessage(message.Payload))
+				if message.Type == InputMessageTypeReady {
+					game.SetReady(cw.id, message.Payload != inputPayloadNotReady)
 				}
 			case <-stop:
 				return
@@ -418,3 +653,89 @@ func (cw *ConnectionWorker) listenPlayerBroadcasts(stop <-chan struct{}, chin <-
 		}
 	}()
 }
+
+// listenPlayerBroadcasts passes chat messages of player accepted by moderator to broadcast. If team is passed team
+// messages are passed to team broadcast, otherwise group messages, emotes and private messages are passed to group
+// broadcast. Returned channel contains errors of rejected messages
+func (cw *ConnectionWorker) listenPlayerBroadcasts(stop <-chan struct{}, chin <-chan InputMessage,
+	b *broadcast.GroupBroadcast, team *teams.Team, moderator *chat.Moderator) <-chan OutputMessage {
+	chout := make(chan OutputMessage, chanOutputMessageBuffer)
+
+	go func() {
+		defer close(chout)
+
+		for {
+			select {
+			case message := <-chin:
+				if (team != nil) != (message.Type == InputMessageTypeTeam) {
+					continue
+				}
+
+				broadcastMessage, ok, err := cw.chatMessage(message, moderator)
+				if !ok {
+					continue
+				}
+				if err != nil {
+					outputMessage := OutputMessage{
+						Type:    OutputMessageTypePlayer,
+						Payload: player.NewMessageError(err.Error()),
+					}
+
+					select {
+					case chout <- outputMessage:
+					default:
+						// Rejections are not important enough to block chat
+					}
+					continue
+				}
+
+				if team != nil {
+					broadcastMessage.Recipient = team.Name()
+				}
+
+				b.BroadcastMessage(broadcastMessage)
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	return chout
+}
+
+// chatMessage creates broadcast message from input message of player. Second returned value is false if input message
+// is not a chat message
+func (cw *ConnectionWorker) chatMessage(message InputMessage, moderator *chat.Moderator) (broadcast.BroadcastMessage,
+	bool, error) {
+	now := time.Now()
+
+	switch message.Type {
+	case InputMessageTypeBroadcast, InputMessageTypeTeam:
+		text, err := moderator.Moderate(cw.id, message.Payload, now)
+		if err != nil {
+			return broadcast.BroadcastMessage{}, true, err
+		}
+		return broadcast.NewChatMessage(cw.id, cw.profile.Nickname, text), true, nil
+	case InputMessageTypeEmote:
+		if !chat.IsEmote(message.Payload) {
+			return broadcast.BroadcastMessage{}, true, chat.ErrUnknownEmote
+		}
+		if _, err := moderator.Moderate(cw.id, message.Payload, now); err != nil {
+			return broadcast.BroadcastMessage{}, true, err
+		}
+		return broadcast.NewEmoteMessage(cw.id, cw.profile.Nickname, message.Payload), true, nil
+	case InputMessageTypePrivate:
+		// Payload of private message is recipient player ID and text separated by space
+		fields := strings.SplitN(strings.TrimSpace(message.Payload), privateMessageSeparator, 2)
+		if len(fields) < 2 {
+			return broadcast.BroadcastMessage{}, true, chat.ErrNoRecipient
+		}
+		text, err := moderator.Moderate(cw.id, fields[1], now)
+		if err != nil {
+			return broadcast.BroadcastMessage{}, true, err
+		}
+		return broadcast.NewPrivateMessage(cw.id, cw.profile.Nickname, fields[0], text), true, nil
+	}
+
+	return broadcast.BroadcastMessage{}, false, nil
+}
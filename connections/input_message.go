@@ -10,11 +10,19 @@ type InputMessageType uint8
 const (
 	InputMessageTypeSnakeCommand InputMessageType = iota
 	InputMessageTypeBroadcast
+	InputMessageTypeTeam
+	InputMessageTypeReady
+	InputMessageTypeEmote
+	InputMessageTypePrivate
 )
 
 var inputMessageTypeJSONs = map[InputMessageType][]byte{
 	InputMessageTypeSnakeCommand: []byte(`"snake"`),
 	InputMessageTypeBroadcast:    []byte(`"broadcast"`),
+	InputMessageTypeTeam:         []byte(`"team"`),
+	InputMessageTypeReady:        []byte(`"ready"`),
+	InputMessageTypeEmote:        []byte(`"emote"`),
+	InputMessageTypePrivate:      []byte(`"private"`),
 }
 
 var ErrUnknownInputMessageType = errors.New("unknown input message type")
@@ -37,3 +37,33 @@ func Test_InputMessageType_UnmarshalJSON_BroadcastMessageTypes(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, expected, inputMessage)
 }
+
+func Test_InputMessageType_UnmarshalJSON_ReadyMessageType(t *testing.T) {
+	data := []byte(`{"type": "ready", "payload": "true"}`)
+	expected := InputMessage{
+		Type:    InputMessageTypeReady,
+		Payload: "true",
+	}
+	var inputMessage InputMessage
+	err := ffjson.Unmarshal(data, &inputMessage)
+	require.Nil(t, err)
+	require.Equal(t, expected, inputMessage)
+}
+
+func Test_InputMessageType_UnmarshalJSON_ChatMessageTypes(t *testing.T) {
+	for data, expected := range map[string]InputMessage{
+		`{"type": "emote", "payload": "gg"}`: {
+			Type:    InputMessageTypeEmote,
+			Payload: "gg",
+		},
+		`{"type": "private", "payload": "5a1f2b3c hello"}`: {
+			Type:    InputMessageTypePrivate,
+			Payload: "5a1f2b3c hello",
+		},
+	} {
+		var inputMessage InputMessage
+		err := ffjson.Unmarshal([]byte(data), &inputMessage)
+		require.Nil(t, err)
+		require.Equal(t, expected, inputMessage)
+	}
+}
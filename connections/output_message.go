@@ -6,12 +6,16 @@ const (
 	OutputMessageTypeGame OutputMessageType = iota
 	OutputMessageTypePlayer
 	OutputMessageTypeBroadcast
+	OutputMessageTypeTeam
+	OutputMessageTypeSession
 )
 
 var outputMessageTypeLabels = map[OutputMessageType]string{
 	OutputMessageTypeGame:      "game",
 	OutputMessageTypePlayer:    "player",
 	OutputMessageTypeBroadcast: "broadcast",
+	OutputMessageTypeTeam:      "team",
+	OutputMessageTypeSession:   "session",
 }
 
 func (t OutputMessageType) String() string {
@@ -25,6 +29,8 @@ var outputMessageTypeJSONs = map[OutputMessageType][]byte{
 	OutputMessageTypeGame:      []byte(`"game"`),
 	OutputMessageTypePlayer:    []byte(`"player"`),
 	OutputMessageTypeBroadcast: []byte(`"broadcast"`),
+	OutputMessageTypeTeam:      []byte(`"team"`),
+	OutputMessageTypeSession:   []byte(`"session"`),
 }
 
 func (t OutputMessageType) MarshalJSON() ([]byte, error) {
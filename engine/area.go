@@ -201,6 +201,32 @@ func (a Area) Navigate(dot Dot, dir Direction, dis uint8) (Dot, error) {
 	}
 }
 
+// Shift calculates and returns dot placed on dx dots horizontally and dy dots vertically from passed dot.
+// Positive dx shifts dot to east, positive dy shifts dot to south
+func (a Area) Shift(dot Dot, dx, dy int) (Dot, error) {
+	var err error
+
+	if dx > 0 {
+		dot, err = a.Navigate(dot, DirectionEast, uint8(dx%int(a.width)))
+	} else if dx < 0 {
+		dot, err = a.Navigate(dot, DirectionWest, uint8(-dx%int(a.width)))
+	}
+	if err != nil {
+		return Dot{}, err
+	}
+
+	if dy > 0 {
+		dot, err = a.Navigate(dot, DirectionSouth, uint8(dy%int(a.height)))
+	} else if dy < 0 {
+		dot, err = a.Navigate(dot, DirectionNorth, uint8(-dy%int(a.height)))
+	}
+	if err != nil {
+		return Dot{}, err
+	}
+
+	return dot, nil
+}
+
 // Implementing json.Marshaler interface
 func (a Area) MarshalJSON() ([]byte, error) {
 	return json.Marshal([]uint8{
@@ -101,3 +101,28 @@ func Test_Area_Navigate_SquareMaxArea255x255(t *testing.T) {
 		require.Equal(t, test.expectedErr, actualErr, fmt.Sprintf("number %d", i))
 	}
 }
+
+func Test_Area_Shift(t *testing.T) {
+	area, err := NewArea(10, 20)
+	require.Nil(t, err)
+
+	tests := []struct {
+		dot    Dot
+		dx, dy int
+		result Dot
+	}{
+		{Dot{X: 5, Y: 5}, 0, 0, Dot{X: 5, Y: 5}},
+		{Dot{X: 5, Y: 5}, 2, -3, Dot{X: 7, Y: 2}},
+		{Dot{X: 5, Y: 5}, -6, 16, Dot{X: 9, Y: 1}},
+		{Dot{X: 0, Y: 0}, 25, -41, Dot{X: 5, Y: 19}},
+	}
+
+	for i, test := range tests {
+		dot, err := area.Shift(test.dot, test.dx, test.dy)
+		require.Nil(t, err, fmt.Sprintf("number of test: %d", i))
+		require.Equal(t, test.result, dot, fmt.Sprintf("number of test: %d", i))
+	}
+
+	_, err = area.Shift(Dot{X: 10, Y: 0}, 1, 1)
+	require.NotNil(t, err)
+}
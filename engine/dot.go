@@ -1,6 +1,10 @@
 package engine
 
-import "fmt"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
 
 type Dot struct {
 	X uint8
@@ -17,6 +21,22 @@ func (d Dot) MarshalJSON() ([]byte, error) {
 	return []byte(fmt.Sprintf("[%d,%d]", d.X, d.Y)), nil
 }
 
+var ErrInvalidDotJSON = errors.New("invalid dot json")
+
+// Implementing json.Unmarshaler interface
+func (d *Dot) UnmarshalJSON(data []byte) error {
+	var coordinates []uint8
+	if err := json.Unmarshal(data, &coordinates); err != nil {
+		return err
+	}
+	if len(coordinates) != 2 {
+		return ErrInvalidDotJSON
+	}
+	d.X = coordinates[0]
+	d.Y = coordinates[1]
+	return nil
+}
+
 func (d Dot) Hash() string {
 	return string([]byte{d.X, d.Y})
 }
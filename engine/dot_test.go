@@ -0,0 +1,21 @@
+package engine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Dot_UnmarshalJSON(t *testing.T) {
+	var dot Dot
+	require.Nil(t, json.Unmarshal([]byte(`[3, 250]`), &dot))
+	require.Equal(t, Dot{X: 3, Y: 250}, dot)
+
+	var location Location
+	require.Nil(t, json.Unmarshal([]byte(`[[1,2],[3,4]]`), &location))
+	require.Equal(t, Location{{X: 1, Y: 2}, {X: 3, Y: 4}}, location)
+
+	require.Equal(t, ErrInvalidDotJSON, json.Unmarshal([]byte(`[1,2,3]`), &dot))
+	require.NotNil(t, json.Unmarshal([]byte(`[1,256]`), &dot))
+}
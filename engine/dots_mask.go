@@ -61,6 +61,14 @@ var DotsMaskDiagonalSmall = NewDotsMask([][]uint8{
 	{1, 0, 1},
 })
 
+var DotsMaskExplosion = NewDotsMask([][]uint8{
+	{0, 0, 1, 0, 0},
+	{0, 1, 1, 1, 0},
+	{1, 1, 1, 1, 1},
+	{0, 1, 1, 1, 0},
+	{0, 0, 1, 0, 0},
+})
+
 var DotsMaskLabyrinth = NewDotsMask([][]uint8{
 	{1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
 	{1, 0, 0, 0, 1, 0, 0, 0, 0, 1},
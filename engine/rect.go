@@ -19,6 +19,14 @@ func NewRect(x, y, w, h uint8) Rect {
 	}
 }
 
+func (r Rect) X() uint8 {
+	return r.x
+}
+
+func (r Rect) Y() uint8 {
+	return r.y
+}
+
 func (r Rect) Width() uint8 {
 	return r.w
 }
@@ -27,6 +35,14 @@ func (r Rect) Height() uint8 {
 	return r.h
 }
 
+// Center returns central dot of rect
+func (r Rect) Center() Dot {
+	return Dot{
+		X: r.x + r.w/2,
+		Y: r.y + r.h/2,
+	}
+}
+
 func (r Rect) ContainsDot(d Dot) bool {
 	return r.x <= d.X && r.y <= d.Y && r.x+r.w > d.X && r.y+r.h > d.Y
 }
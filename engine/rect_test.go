@@ -101,3 +101,8 @@ func Test_Rect_Dot_SquareRectWithXY(t *testing.T) {
 	require.Equal(t, Dot{6, 7}, rect.Dot(7))
 	require.Equal(t, Dot{7, 7}, rect.Dot(8))
 }
+
+func Test_Rect_Center(t *testing.T) {
+	require.Equal(t, Dot{X: 6, Y: 6}, NewRect(5, 5, 3, 3).Center())
+	require.Equal(t, Dot{X: 2, Y: 1}, NewRect(0, 0, 4, 2).Center())
+}
@@ -233,6 +233,13 @@ func (s *Scene) Delete(location Location) *ErrDelete {
 	return s.unsafeDelete(location)
 }
 
+// Clear deletes all locations from scene
+func (s *Scene) Clear() {
+	s.locationsMutex.Lock()
+	defer s.locationsMutex.Unlock()
+	s.locations = make([]Location, 0)
+}
+
 type ErrRelocate struct {
 	Err error
 }
@@ -475,6 +482,15 @@ func (s *Scene) Navigate(dot Dot, dir Direction, dis uint8) (Dot, error) {
 	return s.area.Navigate(dot, dir, dis)
 }
 
+func (s *Scene) Shift(dot Dot, dx, dy int) (Dot, error) {
+	return s.area.Shift(dot, dx, dy)
+}
+
+// RandomRect returns random rect inside scene area regardless of located objects
+func (s *Scene) RandomRect(rw, rh uint8) (*Rect, error) {
+	return s.area.NewRandomRect(rw, rh, 0, 0)
+}
+
 func (s *Scene) Size() uint16 {
 	return s.area.Size()
 }
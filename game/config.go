@@ -0,0 +1,234 @@
+package game
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ivan1993spb/snake-server/bot"
+	"github.com/ivan1993spb/snake-server/chat"
+	"github.com/ivan1993spb/snake-server/objects/registry"
+	"github.com/ivan1993spb/snake-server/player"
+	"github.com/ivan1993spb/snake-server/teams"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+// Config contains game settings
+type Config struct {
+	Width  uint8
+	Height uint8
+
+	// Objects contains labels of object types enabled in game. If Objects is empty all registered types are enabled
+	Objects []string
+	// ObjectOptions contains options of object types by type labels
+	ObjectOptions map[string]registry.Options
+	// Snapshot contains objects which are restored on map when game starts and each time playground is reset
+	Snapshot []*registry.Snapshot
+
+	// Mode is label of game mode. If Mode is empty default mode is used
+	Mode string
+	// ModeOptions contains options of game mode
+	ModeOptions ModeOptions
+
+	// Teams is count of teams. Zero means that game has no teams
+	Teams int
+	// FriendlyFire means that teammates kill each other on touch. Otherwise teammates do not move into each other
+	FriendlyFire bool
+
+	// Lobby enables round lifecycle: players gather in lobby, countdown runs, round plays, results are shown and
+	// playground is reset for the next round
+	Lobby bool
+	// MinPlayers is count of players in lobby which starts the round
+	MinPlayers int
+	// Countdown is duration of countdown before the round
+	Countdown time.Duration
+	// Results is duration of results screen after the round
+	Results time.Duration
+
+	// Respawn contains settings of giving snakes to players
+	Respawn player.Respawn
+
+	// Reconnect contains settings of keeping snakes of disconnected players
+	Reconnect player.Reconnect
+
+	// Idle contains settings of detection of players who send no input
+	Idle Idle
+
+	// Chat contains settings of moderation of messages of players
+	Chat chat.Config
+
+	// Bots contains settings of bots which fill game with few players
+	Bots bot.Config
+
+	// Recorder saves results of snakes to leaderboard. Recorder may be nil
+	Recorder Recorder
+}
+
+// validateLobby checks settings of round lifecycle
+func (c Config) validateLobby() error {
+	if !c.Lobby {
+		return nil
+	}
+	if c.MinPlayers < 1 {
+		return &ErrInvalidLobby{
+			Err: errors.New("min players must be positive"),
+		}
+	}
+	if c.Countdown < 0 || c.Results < 0 {
+		return &ErrInvalidLobby{
+			Err: errors.New("durations must not be negative"),
+		}
+	}
+	return nil
+}
+
+// validateSnapshot restores objects of snapshot into stopped world of game size to check that objects fit the map
+func (c Config) validateSnapshot() error {
+	if len(c.Snapshot) == 0 {
+		return nil
+	}
+
+	w, err := world.NewWorld(c.Width, c.Height)
+	if err != nil {
+		return &ErrInvalidSnapshot{
+			Err: err,
+		}
+	}
+
+	// Events of stopped world are dropped and objects which run until world is stopped exit at once
+	stop := make(chan struct{})
+	close(stop)
+	w.Start(stop)
+
+	for _, s := range c.Snapshot {
+		if _, err := registry.RestoreSnapshot(w.Done(), w, s); err != nil {
+			return &ErrInvalidSnapshot{
+				Err: err,
+			}
+		}
+	}
+
+	return nil
+}
+
+// newTeams creates teams of game or returns nil if game has no teams
+func (c Config) newTeams() (*teams.Teams, error) {
+	if c.Teams == 0 {
+		return nil, nil
+	}
+	t, err := teams.NewTeams(c.Teams, c.FriendlyFire)
+	if err != nil {
+		return nil, &ErrInvalidTeams{
+			Err: err,
+		}
+	}
+	return t, nil
+}
+
+// Validate checks game mode, object types and options
+func (c Config) Validate() error {
+	gameTeams, err := c.newTeams()
+	if err != nil {
+		return err
+	}
+
+	if _, err := NewGameMode(c.Mode, c.ModeOptions, gameTeams); err != nil {
+		return err
+	}
+
+	if err := c.validateLobby(); err != nil {
+		return err
+	}
+
+	if err := c.Respawn.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Reconnect.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Idle.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Chat.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Bots.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.validateSnapshot(); err != nil {
+		return err
+	}
+
+	for _, label := range c.Objects {
+		if _, ok := registry.Get(label); !ok {
+			return &ErrUnknownObjectType{
+				Label: label,
+			}
+		}
+	}
+
+	for label, options := range c.ObjectOptions {
+		t, ok := registry.Get(label)
+		if !ok {
+			return &ErrUnknownObjectType{
+				Label: label,
+			}
+		}
+		if t.NewObserver == nil {
+			continue
+		}
+		if _, err := t.NewObserver(options); err != nil {
+			return &ErrInvalidObjectOptions{
+				Label: label,
+				Err:   err,
+			}
+		}
+	}
+
+	return nil
+}
+
+type ErrInvalidObjectOptions struct {
+	Label string
+	Err   error
+}
+
+func (e *ErrInvalidObjectOptions) Error() string {
+	return "invalid options of object type " + e.Label + ": " + e.Err.Error()
+}
+
+type ErrInvalidTeams struct {
+	Err error
+}
+
+func (e *ErrInvalidTeams) Error() string {
+	return "invalid teams: " + e.Err.Error()
+}
+
+type ErrInvalidLobby struct {
+	Err error
+}
+
+func (e *ErrInvalidLobby) Error() string {
+	return "invalid lobby: " + e.Err.Error()
+}
+
+type ErrUnknownObjectType struct {
+	Label string
+}
+
+func (e *ErrUnknownObjectType) Error() string {
+	return "unknown object type: " + e.Label
+}
+
+type ErrInvalidSnapshot struct {
+	Err error
+}
+
+func (e *ErrInvalidSnapshot) Error() string {
+	return "invalid snapshot: " + e.Err.Error()
+}
@@ -10,6 +10,14 @@ const (
 	EventTypeObjectDelete
 	EventTypeObjectUpdate
 	EventTypeObjectChecked
+	EventTypeExplosion
+	EventTypeMode
+	EventTypeWinner
+	EventTypeCapture
+	EventTypeShrink
+	EventTypePhase
+	EventTypeScoreboard
+	EventTypeDeath
 )
 
 var eventsLabels = map[EventType]string{
@@ -18,6 +26,14 @@ var eventsLabels = map[EventType]string{
 	EventTypeObjectDelete:  "delete",
 	EventTypeObjectUpdate:  "update",
 	EventTypeObjectChecked: "checked",
+	EventTypeExplosion:     "explosion",
+	EventTypeMode:          "mode",
+	EventTypeWinner:        "winner",
+	EventTypeCapture:       "capture",
+	EventTypeShrink:        "shrink",
+	EventTypePhase:         "phase",
+	EventTypeScoreboard:    "scoreboard",
+	EventTypeDeath:         "death",
 }
 
 func (event EventType) String() string {
@@ -33,6 +49,14 @@ var eventTypesJSONs = map[EventType][]byte{
 	EventTypeObjectDelete:  []byte(`"delete"`),
 	EventTypeObjectUpdate:  []byte(`"update"`),
 	EventTypeObjectChecked: []byte(`"checked"`),
+	EventTypeExplosion:     []byte(`"explosion"`),
+	EventTypeMode:          []byte(`"mode"`),
+	EventTypeWinner:        []byte(`"winner"`),
+	EventTypeCapture:       []byte(`"capture"`),
+	EventTypeShrink:        []byte(`"shrink"`),
+	EventTypePhase:         []byte(`"phase"`),
+	EventTypeScoreboard:    []byte(`"scoreboard"`),
+	EventTypeDeath:         []byte(`"death"`),
 }
 
 func (event EventType) MarshalJSON() ([]byte, error) {
@@ -53,6 +77,7 @@ var eventTypesCasting = map[world.EventType]EventType{
 	world.EventTypeObjectDelete:  EventTypeObjectDelete,
 	world.EventTypeObjectUpdate:  EventTypeObjectUpdate,
 	world.EventTypeObjectChecked: EventTypeObjectChecked,
+	world.EventTypeExplosion:     EventTypeExplosion,
 }
 
 func worldEventTypeToGameEventType(worldEventType world.EventType) EventType {
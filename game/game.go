@@ -2,16 +2,53 @@ package game
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/ivan1993spb/snake-server/objects/registry"
 	"github.com/ivan1993spb/snake-server/observers"
+	"github.com/ivan1993spb/snake-server/player"
+	"github.com/ivan1993spb/snake-server/teams"
 	"github.com/ivan1993spb/snake-server/world"
 )
 
+const (
+	gameEventsChanProxyBufferSize = 32
+
+	gameEventsSendTimeout = time.Millisecond * 50
+
+	// Delay between end of a round and start of next round
+	roundRestartDelay = time.Second * 5
+)
+
 type Game struct {
-	world  *world.World
-	logger logrus.FieldLogger
+	world     *world.World
+	objects   []string
+	observers []registry.Observer
+	snapshot  []*registry.Snapshot
+	mode      GameMode
+	teams     *teams.Teams
+	logger    logrus.FieldLogger
+
+	respawn player.Respawn
+	parking *player.Parking
+	idle    Idle
+
+	scoreboard *scoreboard
+	recorder   Recorder
+
+	// lobby is nil if game has no round lifecycle
+	lobby    *lobby
+	phase    PhaseState
+	phaseMux *sync.RWMutex
+
+	// stopObservers stops object observers of current map
+	stopObservers func()
+
+	chsProxy    []chan Event
+	chsProxyMux *sync.RWMutex
 }
 
 type ErrCreateGame struct {
@@ -22,41 +59,358 @@ func (e *ErrCreateGame) Error() string {
 	return "cannot create game: " + e.Err.Error()
 }
 
-func NewGame(logger logrus.FieldLogger, width, height uint8) (*Game, error) {
-	w, err := world.NewWorld(width, height)
+func NewGame(logger logrus.FieldLogger, config Config) (*Game, error) {
+	w, err := world.NewWorld(config.Width, config.Height)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create game: %s", err)
 	}
 
+	gameTeams, err := config.newTeams()
+	if err != nil {
+		return nil, &ErrCreateGame{
+			Err: err,
+		}
+	}
+
+	mode, err := NewGameMode(config.Mode, config.ModeOptions, gameTeams)
+	if err != nil {
+		return nil, &ErrCreateGame{
+			Err: err,
+		}
+	}
+
+	if err := config.validateLobby(); err != nil {
+		return nil, &ErrCreateGame{
+			Err: err,
+		}
+	}
+
+	if err := config.Respawn.Validate(); err != nil {
+		return nil, &ErrCreateGame{
+			Err: err,
+		}
+	}
+
+	if err := config.Reconnect.Validate(); err != nil {
+		return nil, &ErrCreateGame{
+			Err: err,
+		}
+	}
+
+	if err := config.Idle.Validate(); err != nil {
+		return nil, &ErrCreateGame{
+			Err: err,
+		}
+	}
+
+	objects := config.Objects
+	if len(objects) == 0 {
+		objects = registry.Labels()
+	}
+
+	typeObservers := make([]registry.Observer, 0, len(objects))
+
+	for _, label := range objects {
+		t, ok := registry.Get(label)
+		if !ok {
+			return nil, &ErrCreateGame{
+				Err: &ErrUnknownObjectType{
+					Label: label,
+				},
+			}
+		}
+
+		if t.NewObserver == nil {
+			continue
+		}
+
+		observer, err := t.NewObserver(config.ObjectOptions[label])
+		if err != nil {
+			return nil, &ErrCreateGame{
+				Err: &ErrInvalidObjectOptions{
+					Label: label,
+					Err:   err,
+				},
+			}
+		}
+
+		typeObservers = append(typeObservers, observer)
+	}
+
+	if err := config.validateSnapshot(); err != nil {
+		return nil, &ErrCreateGame{
+			Err: err,
+		}
+	}
+
+	var gameLobby *lobby
+	if config.Lobby {
+		gameLobby = newLobby(config)
+	}
+
 	return &Game{
-		world:  w,
-		logger: logger,
+		world:     w,
+		objects:   objects,
+		observers: typeObservers,
+		snapshot:  config.Snapshot,
+		mode:      mode,
+		teams:     gameTeams,
+		logger:    logger,
+
+		respawn: config.Respawn,
+		parking: player.NewParking(config.Reconnect),
+		idle:    config.Idle,
+
+		scoreboard: newScoreboard(),
+		recorder:   config.Recorder,
+
+		lobby:    gameLobby,
+		phase:    gameLobby.initialPhase(),
+		phaseMux: &sync.RWMutex{},
+
+		chsProxy:    make([]chan Event, 0),
+		chsProxyMux: &sync.RWMutex{},
 	}, nil
 }
 
 func (g *Game) Start(stop <-chan struct{}) {
 	g.world.Start(stop)
+	g.parking.Start(stop)
 
 	observers.LoggerObserver{}.Observe(stop, g.world, g.logger)
-	observers.WallObserver{}.Observe(stop, g.world, g.logger)
-	observers.AppleObserver{}.Observe(stop, g.world, g.logger)
-	observers.SnakeObserver{}.Observe(stop, g.world, g.logger)
+
+	g.scoreboard.observe(stop, g.world, g.recorder, g.logger)
+	g.publishScoreboard(stop)
+	g.publishKillFeed(stop)
+
+	g.restoreSnapshot()
+	g.startObservers(stop)
+
+	if source, ok := g.mode.(EventSource); ok {
+		g.forwardModeEvents(stop, source.Events())
+	}
+
+	g.play(stop)
+}
+
+// startObservers runs object observers until game is stopped or map is reset
+func (g *Game) startObservers(stop <-chan struct{}) {
+	mapStop := make(chan struct{})
+	once := &sync.Once{}
+	stopObservers := func() {
+		once.Do(func() {
+			close(mapStop)
+		})
+	}
+	g.stopObservers = stopObservers
+
+	go func() {
+		select {
+		case <-stop:
+			stopObservers()
+		case <-mapStop:
+		}
+	}()
+
+	for _, observer := range g.observers {
+		observer.Observe(mapStop, g.world, g.logger)
+	}
+}
+
+// forwardModeEvents publishes events of game mode
+func (g *Game) forwardModeEvents(stop <-chan struct{}, chin <-chan Event) {
+	go func() {
+		for {
+			select {
+			case event := <-chin:
+				g.publish(event)
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// play runs rounds of game mode and publishes mode states and winners. Game with round lifecycle gathers players
+// in lobby before each round. Playground is reset after each round
+func (g *Game) play(stop <-chan struct{}) {
+	go func() {
+		for {
+			if g.lobby != nil {
+				if !g.waitLobby(stop) || !g.countdown(stop) {
+					return
+				}
+				g.setPhase(PhaseState{
+					Phase: PhasePlay,
+				})
+			}
+
+			var last ModeState
+
+			for state := range g.mode.Play(stop, g.world) {
+				state.Teams = g.TeamScores()
+				last = state
+				g.publish(Event{
+					Type:    EventTypeMode,
+					Payload: state,
+				})
+			}
+
+			select {
+			case <-stop:
+				return
+			default:
+			}
+
+			g.logger.WithFields(logrus.Fields{
+				"mode":   g.mode.Name(),
+				"winner": last.Winner,
+			}).Info("round finished")
+
+			g.publish(Event{
+				Type:    EventTypeWinner,
+				Payload: last.Winner,
+			})
+
+			if g.lobby != nil {
+				if !g.results(stop, last) {
+					return
+				}
+				g.reset(stop)
+				continue
+			}
+
+			timer := time.NewTimer(roundRestartDelay)
+			select {
+			case <-timer.C:
+			case <-stop:
+				timer.Stop()
+				return
+			}
+
+			// Snakes and scores of finished round do not carry over to the next round
+			g.reset(stop)
+			g.setPhase(PhaseState{
+				Phase: PhasePlay,
+			})
+		}
+	}()
+}
+
+// publish sends game event which does not come from world to listeners
+func (g *Game) publish(event Event) {
+	g.chsProxyMux.RLock()
+	defer g.chsProxyMux.RUnlock()
+
+	for _, chProxy := range g.chsProxy {
+		timer := time.NewTimer(gameEventsSendTimeout)
+		select {
+		case chProxy <- event:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+func (g *Game) createChanProxy() chan Event {
+	chProxy := make(chan Event, gameEventsChanProxyBufferSize)
+
+	g.chsProxyMux.Lock()
+	g.chsProxy = append(g.chsProxy, chProxy)
+	g.chsProxyMux.Unlock()
+
+	return chProxy
+}
+
+func (g *Game) deleteChanProxy(chProxy chan Event) {
+	g.chsProxyMux.Lock()
+	for i := range g.chsProxy {
+		if g.chsProxy[i] == chProxy {
+			g.chsProxy = append(g.chsProxy[:i], g.chsProxy[i+1:]...)
+			break
+		}
+	}
+	g.chsProxyMux.Unlock()
 }
 
 func (g *Game) World() *world.World {
 	return g.world
 }
 
+// Objects returns labels of object types enabled in game
+func (g *Game) Objects() []string {
+	objects := make([]string, len(g.objects))
+	copy(objects, g.objects)
+	return objects
+}
+
+// Mode returns label of game mode
+func (g *Game) Mode() string {
+	return g.mode.Name()
+}
+
+// Teams returns teams of game or nil if game has no teams
+func (g *Game) Teams() *teams.Teams {
+	return g.teams
+}
+
+// Respawn returns settings of giving snakes to players
+func (g *Game) Respawn() player.Respawn {
+	return g.respawn
+}
+
+// Parking returns keeper of snakes of disconnected players
+func (g *Game) Parking() *player.Parking {
+	return g.parking
+}
+
+// Idle returns settings of detection of players who send no input
+func (g *Game) Idle() Idle {
+	return g.idle
+}
+
+// AllowSpawn returns true if game mode lets players get new snakes. Players get snakes only while round is going on:
+// not in lobby and not while playground is being reset
+func (g *Game) AllowSpawn() bool {
+	if g.Phase().Phase != PhasePlay {
+		return false
+	}
+	return g.mode.AllowSpawn()
+}
+
 func (g *Game) ListenEvents(stop <-chan struct{}, buffer uint) <-chan Event {
 	chout := make(chan Event, buffer)
+	chProxy := g.createChanProxy()
+	chWorld := g.world.Events(stop, buffer)
+
 	go func() {
 		defer close(chout)
-		for worldEvent := range g.world.Events(stop, buffer) {
+		defer g.deleteChanProxy(chProxy)
+
+		if g.lobby != nil {
 			chout <- Event{
-				Type:    worldEventTypeToGameEventType(worldEvent.Type),
-				Payload: worldEvent.Payload,
+				Type:    EventTypePhase,
+				Payload: g.Phase(),
+			}
+		}
+
+		for {
+			select {
+			case worldEvent, ok := <-chWorld:
+				if !ok {
+					return
+				}
+				chout <- Event{
+					Type:    worldEventTypeToGameEventType(worldEvent.Type),
+					Payload: worldEvent.Payload,
+				}
+			case event := <-chProxy:
+				chout <- event
+			case <-stop:
+				return
 			}
 		}
 	}()
+
 	return chout
 }
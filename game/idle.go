@@ -0,0 +1,88 @@
+package game
+
+import (
+	"time"
+)
+
+// Maximal time without input after which idle player is punished
+const maxIdleTimeout = time.Hour
+
+// Default lead time of warning of idle player
+const defaultIdleWarning = time.Second * 10
+
+// IdleAction decides what happens with idle player
+type IdleAction uint8
+
+const (
+	// IdleActionDisconnect closes connection of idle player
+	IdleActionDisconnect IdleAction = iota
+	// IdleActionSpectate kills snake of idle player and makes player spectator
+	IdleActionSpectate
+)
+
+var idleActionLabels = map[IdleAction]string{
+	IdleActionDisconnect: "disconnect",
+	IdleActionSpectate:   "spectate",
+}
+
+func (a IdleAction) String() string {
+	if label, ok := idleActionLabels[a]; ok {
+		return label
+	}
+	return "unknown"
+}
+
+type ErrInvalidIdle string
+
+func (e ErrInvalidIdle) Error() string {
+	return "invalid idle: " + string(e)
+}
+
+// ParseIdleAction returns idle action by label
+func ParseIdleAction(label string) (IdleAction, error) {
+	for action, actionLabel := range idleActionLabels {
+		if actionLabel == label {
+			return action, nil
+		}
+	}
+	return 0, ErrInvalidIdle("unknown action")
+}
+
+// Idle contains settings of detection of players who send no input
+type Idle struct {
+	// Timeout is time without input after which action is applied to player. Zero disables idle detection
+	Timeout time.Duration
+	// Warning is lead time of notice which warns player about the action
+	Warning time.Duration
+	Action  IdleAction
+}
+
+// DefaultIdle returns settings with disabled idle detection
+func DefaultIdle() Idle {
+	return Idle{
+		Warning: defaultIdleWarning,
+		Action:  IdleActionDisconnect,
+	}
+}
+
+// Enabled returns true if idle players are detected
+func (i Idle) Enabled() bool {
+	return i.Timeout > 0
+}
+
+// Validate checks settings of idle detection
+func (i Idle) Validate() error {
+	if i.Timeout < 0 || i.Timeout > maxIdleTimeout {
+		return ErrInvalidIdle("timeout out of range")
+	}
+	if i.Warning < 0 {
+		return ErrInvalidIdle("negative warning")
+	}
+	if i.Enabled() && i.Warning >= i.Timeout {
+		return ErrInvalidIdle("warning must be shorter than timeout")
+	}
+	if _, ok := idleActionLabels[i.Action]; !ok {
+		return ErrInvalidIdle("unknown action")
+	}
+	return nil
+}
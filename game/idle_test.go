@@ -0,0 +1,44 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Idle_Validate(t *testing.T) {
+	require.Nil(t, DefaultIdle().Validate(), "disabled idle detection")
+	require.Nil(t, Idle{}.Validate())
+
+	require.Nil(t, Idle{
+		Timeout: time.Minute,
+		Warning: time.Second * 10,
+		Action:  IdleActionSpectate,
+	}.Validate())
+
+	for _, idle := range []Idle{
+		{Timeout: -time.Second},
+		{Timeout: time.Hour * 2},
+		{Timeout: time.Minute, Warning: -time.Second},
+		{Timeout: time.Minute, Warning: time.Minute},
+		{Timeout: time.Minute, Action: IdleAction(10)},
+	} {
+		err := idle.Validate()
+		require.NotNil(t, err)
+		require.IsType(t, ErrInvalidIdle(""), err)
+	}
+}
+
+func Test_ParseIdleAction(t *testing.T) {
+	action, err := ParseIdleAction("spectate")
+	require.Nil(t, err)
+	require.Equal(t, IdleActionSpectate, action)
+
+	action, err = ParseIdleAction("disconnect")
+	require.Nil(t, err)
+	require.Equal(t, IdleActionDisconnect, action)
+
+	_, err = ParseIdleAction("ban")
+	require.Equal(t, ErrInvalidIdle("unknown action"), err)
+}
@@ -0,0 +1,25 @@
+package game
+
+import (
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+const chanKillFeedEventsBuffer = 32
+
+// publishKillFeed publishes death of each snake with the cause and the killer
+func (g *Game) publishKillFeed(stop <-chan struct{}) {
+	go func() {
+		for event := range g.world.Events(stop, chanKillFeedEventsBuffer) {
+			if event.Type != world.EventTypeObjectDelete {
+				continue
+			}
+			if s, ok := event.Payload.(*snake.Snake); ok {
+				g.publish(Event{
+					Type:    EventTypeDeath,
+					Payload: s.Death(),
+				})
+			}
+		}
+	}()
+}
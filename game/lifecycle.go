@@ -0,0 +1,345 @@
+package game
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ivan1993spb/snake-server/objects/snake"
+)
+
+// Phase is stage of round lifecycle
+type Phase uint8
+
+const (
+	// PhaseLobby means that players gather before round
+	PhaseLobby Phase = iota
+	// PhaseCountdown means that round starts soon
+	PhaseCountdown
+	// PhasePlay means that round is going on
+	PhasePlay
+	// PhaseResults means that round is finished and rankings are shown
+	PhaseResults
+	// PhaseReset means that playground is being reset for the next round
+	PhaseReset
+)
+
+var phaseLabels = map[Phase]string{
+	PhaseLobby:     "lobby",
+	PhaseCountdown: "countdown",
+	PhasePlay:      "play",
+	PhaseResults:   "results",
+	PhaseReset:     "reset",
+}
+
+func (p Phase) String() string {
+	if label, ok := phaseLabels[p]; ok {
+		return label
+	}
+	return "unknown"
+}
+
+var phaseJSONs = map[Phase][]byte{
+	PhaseLobby:     []byte(`"lobby"`),
+	PhaseCountdown: []byte(`"countdown"`),
+	PhasePlay:      []byte(`"play"`),
+	PhaseResults:   []byte(`"results"`),
+	PhaseReset:     []byte(`"reset"`),
+}
+
+func (p Phase) MarshalJSON() ([]byte, error) {
+	if json, ok := phaseJSONs[p]; ok {
+		return json, nil
+	}
+	return []byte(`"unknown"`), nil
+}
+
+// PhaseState is payload of phase events
+type PhaseState struct {
+	Phase Phase `json:"phase"`
+	// Players is count of players in lobby
+	Players int `json:"players,omitempty"`
+	// Ready is count of players in lobby which are ready to play
+	Ready      int `json:"ready,omitempty"`
+	MinPlayers int `json:"min_players,omitempty"`
+	// Countdown is count of seconds to the start of the round
+	Countdown int            `json:"countdown,omitempty"`
+	Winner    *SnakeSummary  `json:"winner,omitempty"`
+	Rankings  []SnakeRanking `json:"rankings,omitempty"`
+}
+
+// SnakeRanking is place of a snake in results of round
+type SnakeRanking struct {
+	Place int `json:"place"`
+	SnakeSummary
+	// Score is snake score if mode counts scores
+	Score int `json:"score,omitempty"`
+}
+
+// lobby keeps players of game with round lifecycle and their readiness
+type lobby struct {
+	minPlayers int
+	countdown  time.Duration
+	results    time.Duration
+
+	players map[string]bool
+	mux     *sync.RWMutex
+}
+
+func newLobby(config Config) *lobby {
+	return &lobby{
+		minPlayers: config.MinPlayers,
+		countdown:  config.Countdown,
+		results:    config.Results,
+		players:    map[string]bool{},
+		mux:        &sync.RWMutex{},
+	}
+}
+
+// initialPhase returns phase of new game. Game without lifecycle is always in play phase
+func (l *lobby) initialPhase() PhaseState {
+	if l == nil {
+		return PhaseState{
+			Phase: PhasePlay,
+		}
+	}
+	return l.state()
+}
+
+func (l *lobby) join(id string) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.players[id] = false
+}
+
+func (l *lobby) leave(id string) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	delete(l.players, id)
+}
+
+func (l *lobby) setReady(id string, ready bool) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	if _, ok := l.players[id]; ok {
+		l.players[id] = ready
+	}
+}
+
+// unready drops readiness of players. Players confirm readiness before every round
+func (l *lobby) unready() {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	for id := range l.players {
+		l.players[id] = false
+	}
+}
+
+// state returns lobby phase state
+func (l *lobby) state() PhaseState {
+	l.mux.RLock()
+	defer l.mux.RUnlock()
+
+	state := PhaseState{
+		Phase:      PhaseLobby,
+		Players:    len(l.players),
+		MinPlayers: l.minPlayers,
+	}
+	for _, ready := range l.players {
+		if ready {
+			state.Ready++
+		}
+	}
+	return state
+}
+
+// full returns true if round can be started: there are enough players or all players are ready
+func (l *lobby) full(state PhaseState) bool {
+	return state.Players >= l.minPlayers || (state.Players > 0 && state.Ready == state.Players)
+}
+
+// worldResetter is implemented by game modes which keep own objects on playground. Modes drop the objects when
+// playground is reset
+type worldResetter interface {
+	resetWorld()
+}
+
+// Lifecycle returns true if game has round lifecycle
+func (g *Game) Lifecycle() bool {
+	return g.lobby != nil
+}
+
+// JoinLobby adds player to lobby of game with round lifecycle
+func (g *Game) JoinLobby(id string) {
+	if g.lobby != nil {
+		g.lobby.join(id)
+	}
+}
+
+// LeaveLobby removes player from lobby of game with round lifecycle
+func (g *Game) LeaveLobby(id string) {
+	if g.lobby != nil {
+		g.lobby.leave(id)
+	}
+}
+
+// SetReady marks player as ready to play in lobby
+func (g *Game) SetReady(id string, ready bool) {
+	if g.lobby != nil {
+		g.lobby.setReady(id, ready)
+	}
+}
+
+// Phase returns current phase state of round lifecycle
+func (g *Game) Phase() PhaseState {
+	g.phaseMux.RLock()
+	defer g.phaseMux.RUnlock()
+	return g.phase
+}
+
+func (g *Game) setPhase(state PhaseState) {
+	g.phaseMux.Lock()
+	g.phase = state
+	g.phaseMux.Unlock()
+
+	g.publish(Event{
+		Type:    EventTypePhase,
+		Payload: state,
+	})
+}
+
+// waitLobby publishes lobby state until round can be started. It returns false if game is stopped
+func (g *Game) waitLobby(stop <-chan struct{}) bool {
+	state := g.lobby.state()
+	g.setPhase(state)
+
+	ticker := time.NewTicker(modeTickInterval)
+	defer ticker.Stop()
+
+	for !g.lobby.full(state) {
+		select {
+		case <-ticker.C:
+			if next := g.lobby.state(); next.Players != state.Players || next.Ready != state.Ready {
+				state = next
+				g.setPhase(state)
+			}
+		case <-stop:
+			return false
+		}
+	}
+
+	return true
+}
+
+// countdown publishes count of seconds to the start of the round. It returns false if game is stopped
+func (g *Game) countdown(stop <-chan struct{}) bool {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for left := seconds(g.lobby.countdown); left > 0; left-- {
+		g.setPhase(PhaseState{
+			Phase:     PhaseCountdown,
+			Countdown: left,
+		})
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return false
+		}
+	}
+
+	return true
+}
+
+// results publishes rankings of finished round. It returns false if game is stopped
+func (g *Game) results(stop <-chan struct{}, last ModeState) bool {
+	g.setPhase(PhaseState{
+		Phase:    PhaseResults,
+		Winner:   last.Winner,
+		Rankings: rankings(last, snakes(g.world)),
+	})
+
+	timer := time.NewTimer(g.lobby.results)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-stop:
+		return false
+	}
+}
+
+// reset removes snakes and replaces playground objects with fresh map. Protected snakes are removed as well. Map is
+// cleared only when all snakes are stopped. Players in lobby have to get ready again for the next round
+func (g *Game) reset(stop <-chan struct{}) {
+	g.setPhase(PhaseState{
+		Phase: PhaseReset,
+	})
+
+	if g.lobby != nil {
+		g.lobby.unready()
+	}
+
+	g.stopObservers()
+
+	for _, s := range snakes(g.world) {
+		s.Remove(snake.DeathCauseReset)
+	}
+
+	if resetter, ok := g.mode.(worldResetter); ok {
+		resetter.resetWorld()
+	}
+
+	g.world.Clear()
+
+	g.restoreSnapshot()
+	g.startObservers(stop)
+}
+
+// rankings sorts snakes of finished round. Winner goes first, the rest are sorted by scores if mode counts scores
+// or by length
+func rankings(last ModeState, snakes []*snake.Snake) []SnakeRanking {
+	var list []SnakeRanking
+
+	if len(last.Scores) > 0 {
+		for _, score := range last.Scores {
+			list = append(list, SnakeRanking{
+				SnakeSummary: score.SnakeSummary,
+				Score:        score.Score,
+			})
+		}
+	} else {
+		for _, s := range snakes {
+			list = append(list, SnakeRanking{
+				SnakeSummary: *newSnakeSummary(s),
+			})
+		}
+		sort.SliceStable(list, func(i, j int) bool {
+			return list[i].Length > list[j].Length
+		})
+	}
+
+	if last.Winner != nil {
+		found := false
+		for i := range list {
+			if list[i].UUID == last.Winner.UUID {
+				winner := list[i]
+				copy(list[1:i+1], list[:i])
+				list[0] = winner
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append([]SnakeRanking{{SnakeSummary: *last.Winner}}, list...)
+		}
+	}
+
+	for i := range list {
+		list[i].Place = i + 1
+	}
+
+	return list
+}
@@ -0,0 +1,240 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/profile"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+func Test_lobby_full_MinPlayersOrAllReady(t *testing.T) {
+	l := newLobby(Config{
+		MinPlayers: 3,
+	})
+
+	require.False(t, l.full(l.state()), "empty lobby")
+
+	l.join("first")
+	l.join("second")
+	require.False(t, l.full(l.state()))
+
+	l.setReady("first", true)
+	state := l.state()
+	require.Equal(t, 2, state.Players)
+	require.Equal(t, 1, state.Ready)
+	require.False(t, l.full(state))
+
+	l.setReady("second", true)
+	require.True(t, l.full(l.state()), "all players are ready")
+
+	l.setReady("second", false)
+	l.join("third")
+	require.True(t, l.full(l.state()), "enough players")
+
+	l.leave("third")
+	l.setReady("unknown", true)
+	require.Equal(t, 2, l.state().Players)
+}
+
+func Test_rankings_WinnerGoesFirst(t *testing.T) {
+	w, err := world.NewWorld(50, 50)
+	require.Nil(t, err)
+
+	first, err := snake.NewSnake(w, profile.NewDefaultProfile(), nil)
+	require.Nil(t, err)
+	second, err := snake.NewSnake(w, profile.NewDefaultProfile(), nil)
+	require.Nil(t, err)
+
+	list := rankings(ModeState{
+		Winner: newSnakeSummary(second),
+	}, []*snake.Snake{first, second})
+
+	require.Len(t, list, 2)
+	require.Equal(t, 1, list[0].Place)
+	require.Equal(t, second.GetUUID(), list[0].UUID)
+	require.Equal(t, 2, list[1].Place)
+	require.Equal(t, first.GetUUID(), list[1].UUID)
+
+	list = rankings(ModeState{
+		Scores: []SnakeScore{
+			{SnakeSummary: SnakeSummary{UUID: "a"}, Score: 5},
+			{SnakeSummary: SnakeSummary{UUID: "b"}, Score: 3},
+		},
+	}, nil)
+	require.Equal(t, []SnakeRanking{
+		{Place: 1, SnakeSummary: SnakeSummary{UUID: "a"}, Score: 5},
+		{Place: 2, SnakeSummary: SnakeSummary{UUID: "b"}, Score: 3},
+	}, list)
+}
+
+func Test_Game_Lifecycle_LobbyStartsRound(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	g, err := NewGame(logger, Config{
+		Width:      20,
+		Height:     20,
+		Objects:    []string{},
+		Lobby:      true,
+		MinPlayers: 1,
+		Countdown:  time.Second,
+		Results:    time.Second,
+	})
+	require.Nil(t, err)
+	require.True(t, g.Lifecycle())
+	require.Equal(t, PhaseLobby, g.Phase().Phase)
+	require.False(t, g.AllowSpawn(), "players wait in lobby")
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	events := g.ListenEvents(stop, 32)
+	g.Start(stop)
+
+	event := <-events
+	require.Equal(t, EventTypePhase, event.Type)
+	require.Equal(t, PhaseLobby, event.Payload.(PhaseState).Phase)
+
+	g.JoinLobby("player")
+
+	phases := []Phase{}
+	timeout := time.After(time.Second * 5)
+	for len(phases) == 0 || phases[len(phases)-1] != PhasePlay {
+		select {
+		case event := <-events:
+			if event.Type == EventTypePhase {
+				phases = append(phases, event.Payload.(PhaseState).Phase)
+			}
+		case <-timeout:
+			t.Fatal("round is not started", phases)
+		}
+	}
+
+	require.Contains(t, phases, PhaseCountdown)
+	require.True(t, g.AllowSpawn())
+}
+
+func Test_Game_Lifecycle_NextLobbyWaitsForReadyPlayers(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	g, err := NewGame(logger, Config{
+		Width:       20,
+		Height:      20,
+		Objects:     []string{},
+		Mode:        ModeDeathmatch,
+		ModeOptions: ModeOptions{modeOptionDuration: "1s"},
+		Lobby:       true,
+		MinPlayers:  2,
+	})
+	require.Nil(t, err)
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	events := g.ListenEvents(stop, 32)
+	g.Start(stop)
+
+	g.JoinLobby("player")
+	g.SetReady("player", true)
+
+	// The only player is ready: the first round is started, finished and the next lobby is opened
+	phases := []Phase{}
+	timeout := time.After(time.Second * 5)
+	for !containsPhase(phases, PhaseReset) || phases[len(phases)-1] != PhaseLobby {
+		select {
+		case event := <-events:
+			if event.Type == EventTypePhase {
+				phases = append(phases, event.Payload.(PhaseState).Phase)
+			}
+		case <-timeout:
+			t.Fatal("next lobby is not opened", phases)
+		}
+	}
+	require.True(t, containsPhase(phases, PhasePlay))
+
+	state := g.Phase()
+	require.Equal(t, 1, state.Players)
+	require.Equal(t, 0, state.Ready)
+
+	timeout = time.After(modeTickInterval * 2)
+	for {
+		select {
+		case event := <-events:
+			if event.Type == EventTypePhase {
+				require.Equal(t, PhaseLobby, event.Payload.(PhaseState).Phase, "next round is started")
+			}
+		case <-timeout:
+			return
+		}
+	}
+}
+
+func Test_Game_reset_RemovesProtectedSnakes(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	g, err := NewGame(logger, Config{
+		Width:      20,
+		Height:     20,
+		Objects:    []string{},
+		Lobby:      true,
+		MinPlayers: 1,
+	})
+	require.Nil(t, err)
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	g.world.Start(stop)
+	g.startObservers(stop)
+
+	s, err := snake.NewSnake(g.world, &profile.Profile{}, nil)
+	require.Nil(t, err)
+	s.Protect(time.Minute)
+	done := s.Run(stop)
+
+	g.reset(stop)
+
+	select {
+	case <-done:
+	default:
+		t.Fatal("snake is running after reset")
+	}
+	require.Equal(t, snake.DeathCauseReset, s.Death().Cause)
+	require.Empty(t, snakes(g.world))
+}
+
+func Test_Game_AllowSpawn_NotWhileResetWithoutLobby(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	g, err := NewGame(logger, Config{
+		Width:   20,
+		Height:  20,
+		Objects: []string{},
+		Mode:    ModeLength,
+	})
+	require.Nil(t, err)
+	require.True(t, g.AllowSpawn())
+
+	g.setPhase(PhaseState{
+		Phase: PhaseReset,
+	})
+	require.False(t, g.AllowSpawn(), "snakes are not spawned on playground being reset")
+
+	g.setPhase(PhaseState{
+		Phase: PhasePlay,
+	})
+	require.True(t, g.AllowSpawn())
+}
+
+func containsPhase(phases []Phase, phase Phase) bool {
+	for _, p := range phases {
+		if p == phase {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/teams"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+// GameMode defines win conditions of a game. Every call of Play is a round
+type GameMode interface {
+	// Name returns label of the mode
+	Name() string
+	// AllowSpawn returns true if players can get new snakes at the moment
+	AllowSpawn() bool
+	// Play watches the world and sends mode state until the round is finished. The channel is closed when the round
+	// is over. Last sent state contains a winner if somebody won the round
+	Play(stop <-chan struct{}, world *world.World) <-chan ModeState
+}
+
+// ModeOptions contains settings of a game mode passed with game configuration
+type ModeOptions map[string]string
+
+// ModeFactory creates game mode with passed options. Teams are nil if game has no teams
+type ModeFactory func(options ModeOptions, gameTeams *teams.Teams) (GameMode, error)
+
+// Game modes
+const (
+	ModeEndless    = "endless"
+	ModeDeathmatch = "deathmatch"
+	ModeLength     = "length"
+	ModeSurvival   = "survival"
+	ModeCTF        = "ctf"
+	ModeKOTH       = "koth"
+	ModeRoyale     = "royale"
+)
+
+const DefaultMode = ModeEndless
+
+var modeFactories = map[string]ModeFactory{
+	ModeEndless:    newEndlessMode,
+	ModeDeathmatch: newDeathmatchMode,
+	ModeLength:     newLengthMode,
+	ModeSurvival:   newSurvivalMode,
+	ModeCTF:        newCTFMode,
+	ModeKOTH:       newKOTHMode,
+	ModeRoyale:     newRoyaleMode,
+}
+
+// NewGameMode creates game mode by label. Empty label means default mode
+func NewGameMode(name string, options ModeOptions, gameTeams *teams.Teams) (GameMode, error) {
+	if name == "" {
+		name = DefaultMode
+	}
+
+	factory, ok := modeFactories[name]
+	if !ok {
+		return nil, &ErrUnknownMode{
+			Mode: name,
+		}
+	}
+
+	mode, err := factory(options, gameTeams)
+	if err != nil {
+		return nil, &ErrInvalidModeOptions{
+			Mode: name,
+			Err:  err,
+		}
+	}
+
+	return mode, nil
+}
+
+// Modes returns sorted labels of available game modes
+func Modes() []string {
+	modes := make([]string, 0, len(modeFactories))
+	for name := range modeFactories {
+		modes = append(modes, name)
+	}
+	sort.Strings(modes)
+	return modes
+}
+
+type ErrUnknownMode struct {
+	Mode string
+}
+
+func (e *ErrUnknownMode) Error() string {
+	return "unknown game mode: " + e.Mode
+}
+
+type ErrInvalidModeOptions struct {
+	Mode string
+	Err  error
+}
+
+func (e *ErrInvalidModeOptions) Error() string {
+	return "invalid options of game mode " + e.Mode + ": " + e.Err.Error()
+}
+
+type ErrInvalidModeOption struct {
+	Option string
+	Value  string
+}
+
+func (e *ErrInvalidModeOption) Error() string {
+	return fmt.Sprintf("invalid mode option %s: %q", e.Option, e.Value)
+}
+
+// ModeState is pushed to players with mode events
+type ModeState struct {
+	Mode string `json:"mode"`
+	// TimeLeft is count of seconds to the end of the round
+	TimeLeft int `json:"time_left,omitempty"`
+	// Target is length which snake has to reach to win or score in king-of-the-hill mode
+	Target uint16 `json:"target,omitempty"`
+	// Alive is count of snakes which are in the game
+	Alive  int           `json:"alive"`
+	Leader *SnakeSummary `json:"leader,omitempty"`
+	Winner *SnakeSummary `json:"winner,omitempty"`
+	// Teams contains team scores if game has teams
+	Teams []TeamScore `json:"teams,omitempty"`
+	// Bases contains team bases in capture-the-flag mode
+	Bases []BaseSummary `json:"bases,omitempty"`
+	// Captures contains count of captures by team names in capture-the-flag mode
+	Captures map[string]int `json:"captures,omitempty"`
+	// Zones contains control zones in king-of-the-hill mode
+	Zones []ZoneSummary `json:"zones,omitempty"`
+	// Scores contains the best snake scores in king-of-the-hill mode
+	Scores []SnakeScore `json:"scores,omitempty"`
+	// Arena is playable area in battle royale mode
+	Arena *engine.Rect `json:"arena,omitempty"`
+	// ShrinkIn is count of seconds to the next arena shrink in battle royale mode
+	ShrinkIn int `json:"shrink_in,omitempty"`
+}
+
+// SnakeSummary describes snake in mode state
+type SnakeSummary struct {
+	UUID     string `json:"uuid"`
+	Nickname string `json:"nickname,omitempty"`
+	Length   uint16 `json:"length"`
+	Team     string `json:"team,omitempty"`
+}
+
+func newSnakeSummary(s *snake.Snake) *SnakeSummary {
+	summary := &SnakeSummary{
+		UUID:   s.GetUUID(),
+		Length: s.GetLength(),
+	}
+	if p := s.GetProfile(); p != nil {
+		summary.Nickname = p.Nickname
+	}
+	if team := s.GetTeam(); team != nil {
+		summary.Team = team.Name()
+	}
+	return summary
+}
+
+// snakes returns snakes which are on playground
+func snakes(w *world.World) []*snake.Snake {
+	var snakes []*snake.Snake
+	for _, object := range w.GetObjects() {
+		if s, ok := object.(*snake.Snake); ok {
+			snakes = append(snakes, s)
+		}
+	}
+	return snakes
+}
+
+// leader returns the longest snake or nil if there are no snakes
+func leader(snakes []*snake.Snake) *SnakeSummary {
+	var longest *SnakeSummary
+	for _, s := range snakes {
+		if summary := newSnakeSummary(s); longest == nil || summary.Length > longest.Length {
+			longest = summary
+		}
+	}
+	return longest
+}
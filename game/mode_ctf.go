@@ -0,0 +1,212 @@
+package game
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects/flag"
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/teams"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+const modeOptionCaptures = "captures"
+
+const defaultCapturesToWin = 3
+
+const chanModeEventsBuffer = 32
+
+var ErrModeRequiresTeams = errors.New("mode requires teams")
+
+// EventSource is implemented by game modes which emit own game events besides mode state
+type EventSource interface {
+	Events() <-chan Event
+}
+
+// BaseSummary describes team base in mode state
+type BaseSummary struct {
+	Team  string      `json:"team"`
+	Color string      `json:"color"`
+	Rect  engine.Rect `json:"rect"`
+}
+
+// CaptureSummary is payload of capture event
+type CaptureSummary struct {
+	// Team is name of capturing team
+	Team string `json:"team"`
+	// Flag is name of team which flag was captured
+	Flag     string         `json:"flag"`
+	Snake    *SnakeSummary  `json:"snake,omitempty"`
+	Captures map[string]int `json:"captures"`
+}
+
+// ctfMode is capture-the-flag round. The team which captures enemy flags target times wins
+type ctfMode struct {
+	teams  *teams.Teams
+	target int
+
+	field    *flag.Field
+	captures map[*teams.Team]int
+	winner   *SnakeSummary
+	chEvents chan Event
+	mux      *sync.Mutex
+}
+
+func newCTFMode(options ModeOptions, gameTeams *teams.Teams) (GameMode, error) {
+	if gameTeams == nil {
+		return nil, ErrModeRequiresTeams
+	}
+
+	mode := &ctfMode{
+		teams:    gameTeams,
+		target:   defaultCapturesToWin,
+		captures: map[*teams.Team]int{},
+		chEvents: make(chan Event, chanModeEventsBuffer),
+		mux:      &sync.Mutex{},
+	}
+
+	for option, value := range options {
+		if option != modeOptionCaptures {
+			return nil, &ErrInvalidModeOption{option, value}
+		}
+		target, err := strconv.Atoi(value)
+		if err != nil || target <= 0 {
+			return nil, &ErrInvalidModeOption{option, value}
+		}
+		mode.target = target
+	}
+
+	return mode, nil
+}
+
+func (*ctfMode) Name() string {
+	return ModeCTF
+}
+
+func (*ctfMode) AllowSpawn() bool {
+	return true
+}
+
+func (m *ctfMode) Events() <-chan Event {
+	return m.chEvents
+}
+
+func (m *ctfMode) Play(stop <-chan struct{}, w *world.World) <-chan ModeState {
+	m.mux.Lock()
+	m.captures = map[*teams.Team]int{}
+	m.winner = nil
+	field := m.field
+	m.mux.Unlock()
+
+	if field != nil {
+		field.Reset()
+	}
+
+	return modeTicker(stop, func() (ModeState, bool) {
+		m.setupField(stop, w)
+		return m.state(w)
+	})
+}
+
+// setupField creates bases and flags once playground is ready
+func (m *ctfMode) setupField(stop <-chan struct{}, w *world.World) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if m.field != nil {
+		return
+	}
+
+	field, err := flag.NewField(w, m.teams.List())
+	if err != nil {
+		return
+	}
+
+	m.field = field
+
+	go m.listenCaptures(stop, field)
+}
+
+func (m *ctfMode) resetWorld() {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	// Field is set up again on fresh playground
+	m.field = nil
+}
+
+func (m *ctfMode) listenCaptures(stop <-chan struct{}, field *flag.Field) {
+	for {
+		select {
+		case capture := <-field.Captures():
+			m.capture(capture)
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (m *ctfMode) capture(capture flag.Capture) {
+	m.mux.Lock()
+	m.captures[capture.Team]++
+
+	summary := &CaptureSummary{
+		Team:     capture.Team.Name(),
+		Flag:     capture.Flag.Team().Name(),
+		Captures: m.unsafeCaptures(),
+	}
+
+	if s, ok := capture.Carrier.(*snake.Snake); ok {
+		summary.Snake = newSnakeSummary(s)
+	}
+
+	if m.captures[capture.Team] >= m.target && m.winner == nil {
+		m.winner = summary.Snake
+	}
+	m.mux.Unlock()
+
+	select {
+	case m.chEvents <- Event{
+		Type:    EventTypeCapture,
+		Payload: summary,
+	}:
+	default:
+	}
+}
+
+func (m *ctfMode) unsafeCaptures() map[string]int {
+	captures := make(map[string]int, len(m.captures))
+	for _, team := range m.teams.List() {
+		captures[team.Name()] = m.captures[team]
+	}
+	return captures
+}
+
+func (m *ctfMode) state(w *world.World) (ModeState, bool) {
+	snakes := snakes(w)
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	state := ModeState{
+		Mode:     m.Name(),
+		Target:   uint16(m.target),
+		Alive:    len(snakes),
+		Leader:   leader(snakes),
+		Captures: m.unsafeCaptures(),
+		Winner:   m.winner,
+	}
+
+	if m.field != nil {
+		for _, base := range m.field.Bases() {
+			state.Bases = append(state.Bases, BaseSummary{
+				Team:  base.Team.Name(),
+				Color: base.Team.Color(),
+				Rect:  base.Rect,
+			})
+		}
+	}
+
+	return state, m.winner != nil
+}
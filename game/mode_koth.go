@@ -0,0 +1,251 @@
+package game
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/objects/zone"
+	"github.com/ivan1993spb/snake-server/teams"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+// Options of king-of-the-hill mode
+const (
+	modeOptionZones    = "zones"
+	modeOptionSize     = "size"
+	modeOptionRelocate = "relocate"
+	modeOptionScore    = "score"
+)
+
+const (
+	defaultKOTHZones            = 1
+	maxKOTHZones                = 4
+	defaultKOTHZoneSize         = 5
+	minKOTHZoneSize             = 2
+	maxKOTHZoneSize             = 20
+	defaultKOTHRelocateInterval = time.Second * 30
+	defaultKOTHTargetScore      = 60
+	// Only top snakes are sent in mode state
+	maxKOTHScores = 10
+)
+
+// ZoneSummary describes control zone in mode state
+type ZoneSummary struct {
+	UUID string `json:"uuid"`
+	// Owner is the only snake which head is inside the zone
+	Owner *SnakeSummary `json:"owner,omitempty"`
+}
+
+// SnakeScore is score of a snake in king-of-the-hill mode
+type SnakeScore struct {
+	SnakeSummary
+	Score int `json:"score"`
+}
+
+// kothMode is king-of-the-hill round. Snakes get a point for every tick their heads spend in control zones. The
+// first snake which scores target points wins
+type kothMode struct {
+	zonesCount int
+	size       uint8
+	relocate   time.Duration
+	target     int
+
+	zones     []*zone.Zone
+	scores    map[string]*SnakeScore
+	relocated time.Time
+	mux       *sync.Mutex
+}
+
+func newKOTHMode(options ModeOptions, gameTeams *teams.Teams) (GameMode, error) {
+	mode := &kothMode{
+		zonesCount: defaultKOTHZones,
+		size:       defaultKOTHZoneSize,
+		relocate:   defaultKOTHRelocateInterval,
+		target:     defaultKOTHTargetScore,
+		scores:     map[string]*SnakeScore{},
+		mux:        &sync.Mutex{},
+	}
+
+	for option, value := range options {
+		switch option {
+		case modeOptionZones:
+			count, err := strconv.Atoi(value)
+			if err != nil || count <= 0 || count > maxKOTHZones {
+				return nil, &ErrInvalidModeOption{option, value}
+			}
+			mode.zonesCount = count
+		case modeOptionSize:
+			size, err := strconv.ParseUint(value, 10, 8)
+			if err != nil || size < minKOTHZoneSize || size > maxKOTHZoneSize {
+				return nil, &ErrInvalidModeOption{option, value}
+			}
+			mode.size = uint8(size)
+		case modeOptionRelocate:
+			// Zero interval means that zones never relocate
+			interval, err := time.ParseDuration(value)
+			if err != nil || interval < 0 || (interval > 0 && interval < modeTickInterval) {
+				return nil, &ErrInvalidModeOption{option, value}
+			}
+			mode.relocate = interval
+		case modeOptionScore:
+			target, err := strconv.ParseUint(value, 10, 16)
+			if err != nil || target == 0 {
+				return nil, &ErrInvalidModeOption{option, value}
+			}
+			mode.target = int(target)
+		default:
+			return nil, &ErrInvalidModeOption{option, value}
+		}
+	}
+
+	return mode, nil
+}
+
+func (*kothMode) Name() string {
+	return ModeKOTH
+}
+
+func (*kothMode) AllowSpawn() bool {
+	return true
+}
+
+func (m *kothMode) Play(stop <-chan struct{}, w *world.World) <-chan ModeState {
+	m.mux.Lock()
+	m.scores = map[string]*SnakeScore{}
+	m.relocated = time.Now()
+	zones := m.zones
+	m.mux.Unlock()
+
+	for _, z := range zones {
+		z.Relocate()
+	}
+
+	return modeTicker(stop, func() (ModeState, bool) {
+		m.setupZones(w)
+		return m.state(w, time.Now())
+	})
+}
+
+// setupZones creates zones once playground is ready
+func (m *kothMode) setupZones(w *world.World) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	for len(m.zones) < m.zonesCount {
+		z, err := zone.NewZone(w, m.size)
+		if err != nil {
+			return
+		}
+		m.zones = append(m.zones, z)
+	}
+}
+
+func (m *kothMode) resetWorld() {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	// Zones are created again on fresh playground
+	m.zones = nil
+}
+
+func (m *kothMode) state(w *world.World, now time.Time) (ModeState, bool) {
+	snakes := snakes(w)
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	holders := make(map[*zone.Zone][]*snake.Snake, len(m.zones))
+
+	for _, s := range snakes {
+		location := s.GetLocation()
+		if len(location) == 0 {
+			continue
+		}
+
+		head := location[0]
+		inZone := false
+
+		for _, z := range m.zones {
+			if z.Contains(head) {
+				holders[z] = append(holders[z], s)
+				inZone = true
+			}
+		}
+
+		// Snakes score once per tick whatever their speed is: holding a zone is rewarded by time, not by moves
+		if inZone {
+			m.score(s)
+		}
+	}
+
+	state := ModeState{
+		Mode:   m.Name(),
+		Target: uint16(m.target),
+		Alive:  len(snakes),
+		Leader: leader(snakes),
+	}
+
+	for _, z := range m.zones {
+		summary := ZoneSummary{
+			UUID: z.GetUUID(),
+		}
+		if len(holders[z]) == 1 {
+			summary.Owner = newSnakeSummary(holders[z][0])
+			z.SetOwner(summary.Owner.UUID)
+		} else {
+			z.SetOwner("")
+		}
+		state.Zones = append(state.Zones, summary)
+	}
+
+	state.Scores = m.unsafeTopScores()
+
+	if len(state.Scores) > 0 && state.Scores[0].Score >= m.target {
+		winner := state.Scores[0].SnakeSummary
+		state.Winner = &winner
+		return state, true
+	}
+
+	if m.relocate > 0 && now.Sub(m.relocated) >= m.relocate {
+		for _, z := range m.zones {
+			z.Relocate()
+		}
+		m.relocated = now
+	}
+
+	return state, false
+}
+
+// score adds a point to the snake
+func (m *kothMode) score(s *snake.Snake) {
+	summary := newSnakeSummary(s)
+	if score, ok := m.scores[summary.UUID]; ok {
+		score.SnakeSummary = *summary
+		score.Score++
+		return
+	}
+	m.scores[summary.UUID] = &SnakeScore{
+		SnakeSummary: *summary,
+		Score:        1,
+	}
+}
+
+// unsafeTopScores returns the best scores in descending order
+func (m *kothMode) unsafeTopScores() []SnakeScore {
+	scores := make([]SnakeScore, 0, len(m.scores))
+	for _, score := range m.scores {
+		scores = append(scores, *score)
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score == scores[j].Score {
+			return scores[i].UUID < scores[j].UUID
+		}
+		return scores[i].Score > scores[j].Score
+	})
+	if len(scores) > maxKOTHScores {
+		scores = scores[:maxKOTHScores]
+	}
+	return scores
+}
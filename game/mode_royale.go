@@ -0,0 +1,257 @@
+package game
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/objects/wall"
+	"github.com/ivan1993spb/snake-server/teams"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+// Options of battle royale mode
+const (
+	modeOptionInterval = "interval"
+	modeOptionWarning  = "warning"
+	modeOptionStep     = "step"
+	modeOptionMinSize  = "min_size"
+)
+
+const (
+	defaultShrinkInterval = time.Second * 20
+	defaultShrinkWarning  = time.Second * 5
+	defaultShrinkStep     = 2
+	maxShrinkStep         = 10
+	defaultArenaMinSize   = 10
+	minArenaMinSize       = 2
+)
+
+// ShrinkSummary is payload of shrink event
+type ShrinkSummary struct {
+	// Countdown is count of seconds to the shrink. Zero countdown means that arena has shrunk
+	Countdown int         `json:"countdown"`
+	Arena     engine.Rect `json:"arena"`
+}
+
+// royaleMode is battle royale round. Arena is shrunk by a ring of walls once per interval. Snakes caught by the ring
+// die and players cannot get new snakes while round is going on. The last snake standing wins
+type royaleMode struct {
+	*lastStanding
+
+	interval time.Duration
+	warning  time.Duration
+	step     uint8
+	minSize  uint8
+
+	arena    engine.Rect
+	outside  engine.Location
+	shrinkAt time.Time
+	walls    []*wall.Wall
+	chEvents chan Event
+	mux      *sync.Mutex
+}
+
+func newRoyaleMode(options ModeOptions, gameTeams *teams.Teams) (GameMode, error) {
+	mode := &royaleMode{
+		lastStanding: newLastStanding(),
+		interval:     defaultShrinkInterval,
+		warning:      defaultShrinkWarning,
+		step:         defaultShrinkStep,
+		minSize:      defaultArenaMinSize,
+		chEvents:     make(chan Event, chanModeEventsBuffer),
+		mux:          &sync.Mutex{},
+	}
+
+	for option, value := range options {
+		switch option {
+		case modeOptionInterval:
+			interval, err := time.ParseDuration(value)
+			if err != nil || interval < modeTickInterval {
+				return nil, &ErrInvalidModeOption{option, value}
+			}
+			mode.interval = interval
+		case modeOptionWarning:
+			warning, err := time.ParseDuration(value)
+			if err != nil || warning < 0 {
+				return nil, &ErrInvalidModeOption{option, value}
+			}
+			mode.warning = warning
+		case modeOptionStep:
+			step, err := strconv.ParseUint(value, 10, 8)
+			if err != nil || step == 0 || step > maxShrinkStep {
+				return nil, &ErrInvalidModeOption{option, value}
+			}
+			mode.step = uint8(step)
+		case modeOptionMinSize:
+			size, err := strconv.ParseUint(value, 10, 8)
+			if err != nil || size < minArenaMinSize {
+				return nil, &ErrInvalidModeOption{option, value}
+			}
+			mode.minSize = uint8(size)
+		default:
+			return nil, &ErrInvalidModeOption{option, value}
+		}
+	}
+
+	if mode.warning >= mode.interval {
+		return nil, &ErrInvalidModeOption{modeOptionWarning, options[modeOptionWarning]}
+	}
+
+	return mode, nil
+}
+
+func (*royaleMode) Name() string {
+	return ModeRoyale
+}
+
+func (m *royaleMode) Events() <-chan Event {
+	return m.chEvents
+}
+
+func (m *royaleMode) Play(stop <-chan struct{}, w *world.World) <-chan ModeState {
+	m.setStarted(false)
+	m.reset(w)
+
+	return modeTicker(stop, func() (ModeState, bool) {
+		return m.state(w, time.Now())
+	})
+}
+
+// reset removes ring walls and restores full arena
+func (m *royaleMode) reset(w *world.World) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	for _, ring := range m.walls {
+		ring.Destroy()
+	}
+
+	m.walls = nil
+	m.arena = engine.NewRect(0, 0, w.Width(), w.Height())
+	m.outside = nil
+	m.shrinkAt = time.Time{}
+}
+
+func (m *royaleMode) resetWorld() {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.walls = nil
+	m.outside = nil
+}
+
+func (m *royaleMode) state(w *world.World, now time.Time) (ModeState, bool) {
+	snakes := snakes(w)
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	state := ModeState{
+		Mode:   m.Name(),
+		Alive:  len(snakes),
+		Leader: leader(snakes),
+	}
+
+	if m.check(snakes) {
+		state.Winner = state.Leader
+		return state, true
+	}
+
+	if !m.AllowSpawn() {
+		if m.shrinkAt.IsZero() {
+			m.shrinkAt = now.Add(m.interval)
+		}
+
+		if next, ok := m.nextArena(); ok {
+			timeLeft := m.shrinkAt.Sub(now)
+
+			if timeLeft <= 0 {
+				m.shrink(w, next)
+				m.shrinkAt = now.Add(m.interval)
+				m.event(ShrinkSummary{
+					Countdown: 0,
+					Arena:     next,
+				})
+			} else if timeLeft <= m.warning {
+				m.event(ShrinkSummary{
+					Countdown: seconds(timeLeft),
+					Arena:     next,
+				})
+			}
+
+			if _, ok := m.nextArena(); ok {
+				state.ShrinkIn = seconds(m.shrinkAt.Sub(now))
+			}
+		}
+
+		m.enforce(w, snakes)
+	}
+
+	arena := m.arena
+	state.Arena = &arena
+
+	return state, false
+}
+
+// nextArena returns arena after the next shrink. It returns false if arena cannot be shrunk anymore
+func (m *royaleMode) nextArena() (engine.Rect, bool) {
+	indent := m.step * 2
+	if m.arena.Width() < m.minSize+indent || m.arena.Height() < m.minSize+indent {
+		return engine.Rect{}, false
+	}
+
+	return engine.NewRect(m.arena.X()+m.step, m.arena.Y()+m.step, m.arena.Width()-indent, m.arena.Height()-indent), true
+}
+
+func (m *royaleMode) shrink(w *world.World, arena engine.Rect) {
+	m.arena = arena
+	m.outside = make(engine.Location, 0, w.Size()-arena.DotCount())
+
+	for x := uint8(0); x < w.Width(); x++ {
+		for y := uint8(0); y < w.Height(); y++ {
+			if dot := (engine.Dot{X: x, Y: y}); !arena.ContainsDot(dot) {
+				m.outside = append(m.outside, dot)
+			}
+		}
+	}
+}
+
+// enforce kills snakes caught by the ring and fills free dots outside arena with walls
+func (m *royaleMode) enforce(w *world.World, snakes []*snake.Snake) {
+	if len(m.outside) == 0 {
+		return
+	}
+
+	for _, s := range snakes {
+		location := s.GetLocation()
+		for _, dot := range location {
+			if !m.arena.ContainsDot(dot) {
+				s.KillBy(location[0], snake.DeathCauseBorder)
+				break
+			}
+		}
+	}
+
+	if free := w.FreeDots(m.outside); len(free) > 0 {
+		if ring, err := wall.NewWallLocation(w, free); err == nil {
+			m.walls = append(m.walls, ring)
+		}
+	}
+}
+
+func (m *royaleMode) event(summary ShrinkSummary) {
+	select {
+	case m.chEvents <- Event{
+		Type:    EventTypeShrink,
+		Payload: summary,
+	}:
+	default:
+	}
+}
+
+// seconds rounds duration up to seconds
+func seconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
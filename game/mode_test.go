@@ -0,0 +1,281 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects/flag"
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/profile"
+	"github.com/ivan1993spb/snake-server/teams"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+func Test_NewGameMode_DefaultMode(t *testing.T) {
+	mode, err := NewGameMode("", nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, ModeEndless, mode.Name())
+}
+
+func Test_NewGameMode_ValidatesModeAndOptions(t *testing.T) {
+	_, err := NewGameMode("unknown", nil, nil)
+	require.IsType(t, &ErrUnknownMode{}, err)
+
+	_, err = NewGameMode(ModeDeathmatch, ModeOptions{modeOptionDuration: "abc"}, nil)
+	require.IsType(t, &ErrInvalidModeOptions{}, err)
+
+	_, err = NewGameMode(ModeLength, ModeOptions{modeOptionLength: "0"}, nil)
+	require.IsType(t, &ErrInvalidModeOptions{}, err)
+
+	_, err = NewGameMode(ModeEndless, ModeOptions{modeOptionLength: "10"}, nil)
+	require.IsType(t, &ErrInvalidModeOptions{}, err)
+
+	mode, err := NewGameMode(ModeDeathmatch, ModeOptions{modeOptionDuration: "1m"}, nil)
+	require.Nil(t, err)
+	require.Equal(t, time.Minute, mode.(deathmatchMode).duration)
+
+	mode, err = NewGameMode(ModeLength, ModeOptions{modeOptionLength: "12"}, nil)
+	require.Nil(t, err)
+	require.Equal(t, uint16(12), mode.(lengthMode).target)
+
+	_, err = NewGameMode(ModeKOTH, ModeOptions{modeOptionZones: "5"}, nil)
+	require.IsType(t, &ErrInvalidModeOptions{}, err)
+
+	mode, err = NewGameMode(ModeKOTH, ModeOptions{modeOptionRelocate: "0", modeOptionSize: "3"}, nil)
+	require.Nil(t, err)
+	require.Equal(t, time.Duration(0), mode.(*kothMode).relocate)
+	require.Equal(t, uint8(3), mode.(*kothMode).size)
+}
+
+func Test_deathmatchMode_state_LeaderWinsAtDeadline(t *testing.T) {
+	w, err := world.NewWorld(50, 50)
+	require.Nil(t, err)
+
+	s, err := snake.NewSnake(w, profile.NewDefaultProfile(), nil)
+	require.Nil(t, err)
+
+	mode := deathmatchMode{
+		duration: time.Minute,
+	}
+
+	state, finished := mode.state(w, time.Now().Add(time.Second*30))
+	require.False(t, finished)
+	require.Equal(t, 30, state.TimeLeft)
+	require.Equal(t, s.GetUUID(), state.Leader.UUID)
+	require.Nil(t, state.Winner)
+
+	state, finished = mode.state(w, time.Now())
+	require.True(t, finished)
+	require.Equal(t, s.GetUUID(), state.Winner.UUID)
+}
+
+func Test_lengthMode_state_SnakeReachingTargetWins(t *testing.T) {
+	w, err := world.NewWorld(50, 50)
+	require.Nil(t, err)
+
+	s, err := snake.NewSnake(w, profile.NewDefaultProfile(), nil)
+	require.Nil(t, err)
+
+	state, finished := lengthMode{target: s.GetLength() + 1}.state(w)
+	require.False(t, finished)
+	require.Nil(t, state.Winner)
+
+	state, finished = lengthMode{target: s.GetLength()}.state(w)
+	require.True(t, finished)
+	require.Equal(t, s.GetUUID(), state.Winner.UUID)
+}
+
+func Test_survivalMode_state_LastSnakeWins(t *testing.T) {
+	w, err := world.NewWorld(50, 50)
+	require.Nil(t, err)
+
+	mode, err := newSurvivalMode(nil, nil)
+	require.Nil(t, err)
+	survival := mode.(*survivalMode)
+
+	first, err := snake.NewSnake(w, profile.NewDefaultProfile(), nil)
+	require.Nil(t, err)
+
+	_, finished := survival.state(w)
+	require.False(t, finished)
+	require.True(t, survival.AllowSpawn(), "round starts with two snakes")
+
+	second, err := snake.NewSnake(w, profile.NewDefaultProfile(), nil)
+	require.Nil(t, err)
+
+	_, finished = survival.state(w)
+	require.False(t, finished)
+	require.False(t, survival.AllowSpawn(), "round is going on")
+
+	require.Nil(t, w.DeleteObject(second, second.GetLocation()))
+
+	state, finished := survival.state(w)
+	require.True(t, finished)
+	require.Equal(t, first.GetUUID(), state.Winner.UUID)
+	require.True(t, survival.AllowSpawn())
+}
+
+func Test_newCTFMode_RequiresTeams(t *testing.T) {
+	_, err := NewGameMode(ModeCTF, nil, nil)
+	require.IsType(t, &ErrInvalidModeOptions{}, err)
+	require.Equal(t, ErrModeRequiresTeams, err.(*ErrInvalidModeOptions).Err)
+}
+
+func Test_ctfMode_capture_TeamReachingTargetWins(t *testing.T) {
+	w, err := world.NewWorld(60, 60)
+	require.Nil(t, err)
+
+	gameTeams, err := teams.NewTeams(2, true)
+	require.Nil(t, err)
+	red, _ := gameTeams.Get("red")
+	blue, _ := gameTeams.Get("blue")
+
+	mode, err := NewGameMode(ModeCTF, ModeOptions{modeOptionCaptures: "2"}, gameTeams)
+	require.Nil(t, err)
+	ctf := mode.(*ctfMode)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	ctf.setupField(stop, w)
+
+	state, finished := ctf.state(w)
+	require.False(t, finished)
+	require.Len(t, state.Bases, 2)
+	require.Equal(t, map[string]int{"red": 0, "blue": 0}, state.Captures)
+
+	s, err := snake.NewSnake(w, profile.NewDefaultProfile(), red)
+	require.Nil(t, err)
+
+	blueFlag := ctf.field.Flags()[1]
+	require.Equal(t, blue, blueFlag.Team())
+
+	ctf.capture(flag.Capture{Flag: blueFlag, Team: red, Carrier: s})
+	event := <-ctf.Events()
+	require.Equal(t, EventTypeCapture, event.Type)
+	require.Equal(t, map[string]int{"red": 1, "blue": 0}, event.Payload.(*CaptureSummary).Captures)
+
+	_, finished = ctf.state(w)
+	require.False(t, finished)
+
+	ctf.capture(flag.Capture{Flag: blueFlag, Team: red, Carrier: s})
+	state, finished = ctf.state(w)
+	require.True(t, finished)
+	require.Equal(t, s.GetUUID(), state.Winner.UUID)
+	require.Equal(t, "red", state.Winner.Team)
+}
+
+func Test_kothMode_state_SnakeInZoneScoresAndWins(t *testing.T) {
+	w, err := world.NewWorld(20, 20)
+	require.Nil(t, err)
+
+	s, err := snake.NewSnake(w, profile.NewDefaultProfile(), nil)
+	require.Nil(t, err)
+
+	mode, err := newKOTHMode(ModeOptions{
+		modeOptionSize:     "20",
+		modeOptionScore:    "2",
+		modeOptionRelocate: "0",
+	}, nil)
+	require.Nil(t, err)
+	koth := mode.(*kothMode)
+
+	// The zone covers whole playground
+	koth.setupZones(w)
+	require.Len(t, koth.zones, 1)
+
+	state, finished := koth.state(w, time.Now())
+	require.False(t, finished)
+	require.Len(t, state.Zones, 1)
+	require.Equal(t, s.GetUUID(), state.Zones[0].Owner.UUID)
+	require.Equal(t, s.GetUUID(), koth.zones[0].Owner())
+	require.Len(t, state.Scores, 1)
+	require.Equal(t, 1, state.Scores[0].Score)
+
+	state, finished = koth.state(w, time.Now())
+	require.True(t, finished)
+	require.Equal(t, s.GetUUID(), state.Winner.UUID)
+}
+
+func Test_kothMode_state_ContestedZoneHasNoOwner(t *testing.T) {
+	w, err := world.NewWorld(20, 20)
+	require.Nil(t, err)
+
+	_, err = snake.NewSnake(w, profile.NewDefaultProfile(), nil)
+	require.Nil(t, err)
+	_, err = snake.NewSnake(w, profile.NewDefaultProfile(), nil)
+	require.Nil(t, err)
+
+	mode, err := newKOTHMode(ModeOptions{modeOptionSize: "20"}, nil)
+	require.Nil(t, err)
+	koth := mode.(*kothMode)
+	koth.setupZones(w)
+
+	state, finished := koth.state(w, time.Now())
+	require.False(t, finished)
+	require.Nil(t, state.Zones[0].Owner)
+	require.Len(t, state.Scores, 2)
+}
+
+func Test_royaleMode_state_ShrinksArenaWithWarning(t *testing.T) {
+	w, err := world.NewWorld(30, 30)
+	require.Nil(t, err)
+
+	mode, err := newRoyaleMode(ModeOptions{
+		modeOptionInterval: "10s",
+		modeOptionWarning:  "3s",
+		modeOptionStep:     "2",
+		modeOptionMinSize:  "10",
+	}, nil)
+	require.Nil(t, err)
+	royale := mode.(*royaleMode)
+	royale.reset(w)
+
+	_, err = snake.NewSnake(w, profile.NewDefaultProfile(), nil)
+	require.Nil(t, err)
+	_, err = snake.NewSnake(w, profile.NewDefaultProfile(), nil)
+	require.Nil(t, err)
+
+	now := time.Now()
+
+	state, finished := royale.state(w, now)
+	require.False(t, finished)
+	require.False(t, royale.AllowSpawn(), "round is going on")
+	require.Equal(t, 10, state.ShrinkIn)
+	require.Equal(t, engine.NewRect(0, 0, 30, 30), *state.Arena)
+
+	royale.state(w, now.Add(time.Second*8))
+	event := <-royale.Events()
+	require.Equal(t, EventTypeShrink, event.Type)
+	require.Equal(t, ShrinkSummary{Countdown: 2, Arena: engine.NewRect(2, 2, 26, 26)}, event.Payload)
+
+	state, finished = royale.state(w, now.Add(time.Second*10))
+	require.False(t, finished)
+	require.Equal(t, engine.NewRect(2, 2, 26, 26), *state.Arena)
+	event = <-royale.Events()
+	require.Equal(t, 0, event.Payload.(ShrinkSummary).Countdown)
+	require.Empty(t, w.FreeDots(royale.outside), "ring dots are occupied")
+	require.Len(t, royale.outside, 30*30-26*26)
+
+	royale.reset(w)
+	require.Empty(t, royale.walls)
+	require.Empty(t, royale.outside)
+}
+
+func Test_royaleMode_nextArena_StopsAtMinSize(t *testing.T) {
+	mode := &royaleMode{
+		step:    2,
+		minSize: 10,
+		arena:   engine.NewRect(0, 0, 14, 20),
+	}
+
+	arena, ok := mode.nextArena()
+	require.True(t, ok)
+	require.Equal(t, engine.NewRect(2, 2, 10, 16), arena)
+
+	mode.arena = arena
+	_, ok = mode.nextArena()
+	require.False(t, ok)
+}
@@ -0,0 +1,295 @@
+package game
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/teams"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+// Mode state is sent once per modeTickInterval
+const modeTickInterval = time.Second
+
+const chanModeStateBuffer = 8
+
+// Options of game modes
+const (
+	modeOptionDuration = "duration"
+	modeOptionLength   = "length"
+)
+
+const (
+	defaultDeathmatchDuration = time.Minute * 3
+	defaultTargetLength       = 30
+	// Survival round starts when survivalMinSnakes snakes are on playground
+	survivalMinSnakes = 2
+)
+
+// modeTicker calls tick once per modeTickInterval and sends returned state. If tick returns finished state, the
+// state is sent and the round is over
+func modeTicker(stop <-chan struct{}, tick func() (ModeState, bool)) <-chan ModeState {
+	chout := make(chan ModeState, chanModeStateBuffer)
+
+	go func() {
+		defer close(chout)
+
+		ticker := time.NewTicker(modeTickInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				state, finished := tick()
+				select {
+				case chout <- state:
+				case <-stop:
+					return
+				}
+				if finished {
+					return
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	return chout
+}
+
+func checkNoOptions(options ModeOptions) error {
+	for option, value := range options {
+		return &ErrInvalidModeOption{option, value}
+	}
+	return nil
+}
+
+// endlessMode is free-for-all without winners
+type endlessMode struct{}
+
+func newEndlessMode(options ModeOptions, gameTeams *teams.Teams) (GameMode, error) {
+	if err := checkNoOptions(options); err != nil {
+		return nil, err
+	}
+	return endlessMode{}, nil
+}
+
+func (endlessMode) Name() string {
+	return ModeEndless
+}
+
+func (endlessMode) AllowSpawn() bool {
+	return true
+}
+
+func (m endlessMode) Play(stop <-chan struct{}, w *world.World) <-chan ModeState {
+	return modeTicker(stop, func() (ModeState, bool) {
+		return m.state(w)
+	})
+}
+
+func (m endlessMode) state(w *world.World) (ModeState, bool) {
+	snakes := snakes(w)
+	return ModeState{
+		Mode:   m.Name(),
+		Alive:  len(snakes),
+		Leader: leader(snakes),
+	}, false
+}
+
+// deathmatchMode is timed round. The longest snake at the end of the round wins
+type deathmatchMode struct {
+	duration time.Duration
+}
+
+func newDeathmatchMode(options ModeOptions, gameTeams *teams.Teams) (GameMode, error) {
+	mode := deathmatchMode{
+		duration: defaultDeathmatchDuration,
+	}
+
+	for option, value := range options {
+		if option != modeOptionDuration {
+			return nil, &ErrInvalidModeOption{option, value}
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration < modeTickInterval {
+			return nil, &ErrInvalidModeOption{option, value}
+		}
+		mode.duration = duration
+	}
+
+	return mode, nil
+}
+
+func (deathmatchMode) Name() string {
+	return ModeDeathmatch
+}
+
+func (deathmatchMode) AllowSpawn() bool {
+	return true
+}
+
+func (m deathmatchMode) Play(stop <-chan struct{}, w *world.World) <-chan ModeState {
+	deadline := time.Now().Add(m.duration)
+
+	return modeTicker(stop, func() (ModeState, bool) {
+		return m.state(w, deadline)
+	})
+}
+
+func (m deathmatchMode) state(w *world.World, deadline time.Time) (ModeState, bool) {
+	snakes := snakes(w)
+	state := ModeState{
+		Mode:   m.Name(),
+		Alive:  len(snakes),
+		Leader: leader(snakes),
+	}
+
+	timeLeft := deadline.Sub(time.Now())
+	if timeLeft > 0 {
+		state.TimeLeft = seconds(timeLeft)
+		return state, false
+	}
+
+	state.Winner = state.Leader
+	return state, true
+}
+
+// lengthMode is round in which the first snake reaching target length wins
+type lengthMode struct {
+	target uint16
+}
+
+func newLengthMode(options ModeOptions, gameTeams *teams.Teams) (GameMode, error) {
+	mode := lengthMode{
+		target: defaultTargetLength,
+	}
+
+	for option, value := range options {
+		if option != modeOptionLength {
+			return nil, &ErrInvalidModeOption{option, value}
+		}
+		target, err := strconv.ParseUint(value, 10, 16)
+		if err != nil || target == 0 {
+			return nil, &ErrInvalidModeOption{option, value}
+		}
+		mode.target = uint16(target)
+	}
+
+	return mode, nil
+}
+
+func (lengthMode) Name() string {
+	return ModeLength
+}
+
+func (lengthMode) AllowSpawn() bool {
+	return true
+}
+
+func (m lengthMode) Play(stop <-chan struct{}, w *world.World) <-chan ModeState {
+	return modeTicker(stop, func() (ModeState, bool) {
+		return m.state(w)
+	})
+}
+
+func (m lengthMode) state(w *world.World) (ModeState, bool) {
+	snakes := snakes(w)
+	state := ModeState{
+		Mode:   m.Name(),
+		Target: m.target,
+		Alive:  len(snakes),
+		Leader: leader(snakes),
+	}
+
+	if state.Leader != nil && state.Leader.Length >= m.target {
+		state.Winner = state.Leader
+		return state, true
+	}
+
+	return state, false
+}
+
+// lastStanding is round state of modes in which the last snake standing wins. Players cannot get new snakes while
+// round is going on
+type lastStanding struct {
+	started bool
+	mux     *sync.RWMutex
+}
+
+func newLastStanding() *lastStanding {
+	return &lastStanding{
+		mux: &sync.RWMutex{},
+	}
+}
+
+func (l *lastStanding) AllowSpawn() bool {
+	l.mux.RLock()
+	defer l.mux.RUnlock()
+	return !l.started
+}
+
+func (l *lastStanding) setStarted(started bool) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.started = started
+}
+
+// check starts round when survivalMinSnakes snakes are on playground and finishes started round when one snake or
+// none is left. It returns true if round is finished
+func (l *lastStanding) check(snakes []*snake.Snake) bool {
+	if !l.AllowSpawn() {
+		if len(snakes) <= 1 {
+			l.setStarted(false)
+			return true
+		}
+	} else if len(snakes) >= survivalMinSnakes {
+		l.setStarted(true)
+	}
+	return false
+}
+
+// survivalMode is round in which the last snake standing wins. Players cannot get new snakes while round is going on
+type survivalMode struct {
+	*lastStanding
+}
+
+func newSurvivalMode(options ModeOptions, gameTeams *teams.Teams) (GameMode, error) {
+	if err := checkNoOptions(options); err != nil {
+		return nil, err
+	}
+	return &survivalMode{
+		lastStanding: newLastStanding(),
+	}, nil
+}
+
+func (*survivalMode) Name() string {
+	return ModeSurvival
+}
+
+func (m *survivalMode) Play(stop <-chan struct{}, w *world.World) <-chan ModeState {
+	m.setStarted(false)
+
+	return modeTicker(stop, func() (ModeState, bool) {
+		return m.state(w)
+	})
+}
+
+func (m *survivalMode) state(w *world.World) (ModeState, bool) {
+	snakes := snakes(w)
+	state := ModeState{
+		Mode:   m.Name(),
+		Alive:  len(snakes),
+		Leader: leader(snakes),
+	}
+
+	if m.check(snakes) {
+		state.Winner = state.Leader
+		return state, true
+	}
+
+	return state, false
+}
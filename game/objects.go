@@ -0,0 +1,12 @@
+package game
+
+// Built-in object types register themselves in object registry
+import (
+	_ "github.com/ivan1993spb/snake-server/objects/apple"
+	_ "github.com/ivan1993spb/snake-server/objects/bomb"
+	_ "github.com/ivan1993spb/snake-server/objects/corpse"
+	_ "github.com/ivan1993spb/snake-server/objects/flag"
+	_ "github.com/ivan1993spb/snake-server/objects/snake"
+	_ "github.com/ivan1993spb/snake-server/objects/wall"
+	_ "github.com/ivan1993spb/snake-server/objects/zone"
+)
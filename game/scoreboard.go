@@ -0,0 +1,257 @@
+package game
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/leaderboard"
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+const chanScoreboardEventsBuffer = 64
+
+// Interval of pushing scoreboard to players
+const scoreboardInterval = time.Second * 2
+
+// ScoreboardRow contains statistics of player in game
+type ScoreboardRow struct {
+	// Player is stable player id or snake uuid if player has no session
+	Player   string `json:"player"`
+	Nickname string `json:"nickname"`
+	// Snake is uuid of living snake of player
+	Snake     string `json:"snake,omitempty"`
+	Length    uint16 `json:"length"`
+	MaxLength uint16 `json:"max_length"`
+	Kills     uint16 `json:"kills"`
+	Deaths    uint16 `json:"deaths"`
+	// Food is nutrition eaten by snakes of player
+	Food uint32 `json:"food"`
+	// TimeAlive is seconds which snakes of player spent alive
+	TimeAlive uint32 `json:"time_alive"`
+	// Bot means that player is controlled by server
+	Bot bool `json:"bot"`
+}
+
+// Recorder saves results of snakes when they die
+type Recorder interface {
+	Record(entry leaderboard.Entry) error
+}
+
+// scoreboard gathers statistics of players from world events
+type scoreboard struct {
+	rows map[string]*playerStats
+	// living contains stats of living snakes by uuid
+	living map[string]*livingSnake
+	mux    *sync.RWMutex
+}
+
+type playerStats struct {
+	row       ScoreboardRow
+	timeAlive time.Duration
+	// snake is living snake of player or nil
+	snake *livingSnake
+}
+
+type livingSnake struct {
+	snake     *snake.Snake
+	player    string
+	length    uint16
+	maxLength uint16
+	born      time.Time
+}
+
+func newScoreboard() *scoreboard {
+	return &scoreboard{
+		rows:   map[string]*playerStats{},
+		living: map[string]*livingSnake{},
+		mux:    &sync.RWMutex{},
+	}
+}
+
+// observe feeds scoreboard with world events until stop is closed. Results of dead snakes are passed to recorder if
+// recorder is not nil
+func (sb *scoreboard) observe(stop <-chan struct{}, w *world.World, recorder Recorder, logger logrus.FieldLogger) {
+	go func() {
+		for event := range w.Events(stop, chanScoreboardEventsBuffer) {
+			s, ok := event.Payload.(*snake.Snake)
+			if !ok {
+				continue
+			}
+			switch event.Type {
+			case world.EventTypeObjectCreate, world.EventTypeObjectUpdate:
+				sb.update(s, time.Now())
+			case world.EventTypeObjectDelete:
+				entry, ok := sb.delete(s, s.Death(), time.Now())
+				if ok && recorder != nil {
+					if err := recorder.Record(entry); err != nil {
+						logger.WithError(err).Error("cannot record result of snake")
+					}
+				}
+			}
+		}
+	}()
+}
+
+// playerKey returns id of player of snake
+func playerKey(s *snake.Snake) string {
+	if p := s.GetProfile(); p != nil && p.ID != "" {
+		return p.ID
+	}
+	return s.GetUUID()
+}
+
+// update counts growth of snake as eaten food
+func (sb *scoreboard) update(s *snake.Snake, now time.Time) {
+	sb.mux.Lock()
+	defer sb.mux.Unlock()
+
+	length := s.GetLength()
+
+	living, ok := sb.living[s.GetUUID()]
+	if !ok {
+		living = &livingSnake{
+			snake:     s,
+			player:    playerKey(s),
+			length:    length,
+			maxLength: length,
+			born:      now,
+		}
+		sb.living[s.GetUUID()] = living
+	}
+
+	stats, ok := sb.rows[living.player]
+	if !ok {
+		stats = &playerStats{
+			row: ScoreboardRow{
+				Player: living.player,
+			},
+		}
+		sb.rows[living.player] = stats
+	}
+
+	if p := s.GetProfile(); p != nil {
+		stats.row.Nickname = p.Nickname
+		stats.row.Bot = p.Bot
+	}
+	stats.snake = living
+
+	if length > living.length {
+		stats.row.Food += uint32(length - living.length)
+	}
+	living.length = length
+	if length > living.maxLength {
+		living.maxLength = length
+	}
+
+	if length > stats.row.MaxLength {
+		stats.row.MaxLength = length
+	}
+}
+
+// delete counts death of snake. Snakes removed on playground reset are not counted as dead. It returns result of the
+// snake for leaderboard. Results of bots and of snakes removed on reset are not returned
+func (sb *scoreboard) delete(s *snake.Snake, death snake.Death, now time.Time) (leaderboard.Entry, bool) {
+	sb.mux.Lock()
+	defer sb.mux.Unlock()
+
+	living, ok := sb.living[s.GetUUID()]
+	if !ok {
+		return leaderboard.Entry{}, false
+	}
+	delete(sb.living, s.GetUUID())
+
+	stats, ok := sb.rows[living.player]
+	if !ok {
+		return leaderboard.Entry{}, false
+	}
+
+	if death.Cause != snake.DeathCauseReset {
+		stats.row.Deaths++
+	}
+	stats.row.Kills += s.GetKills()
+	stats.timeAlive += now.Sub(living.born)
+	if stats.snake == living {
+		stats.snake = nil
+	}
+
+	if stats.row.Bot || death.Cause == snake.DeathCauseReset {
+		return leaderboard.Entry{}, false
+	}
+
+	return leaderboard.Entry{
+		Player:   living.player,
+		Nickname: stats.row.Nickname,
+		Length:   living.maxLength,
+		Kills:    s.GetKills(),
+		Time:     now,
+	}, true
+}
+
+// list returns rows of scoreboard sorted by current length, max length and kills
+func (sb *scoreboard) list(now time.Time) []ScoreboardRow {
+	sb.mux.RLock()
+	defer sb.mux.RUnlock()
+
+	rows := make([]ScoreboardRow, 0, len(sb.rows))
+
+	for _, stats := range sb.rows {
+		row := stats.row
+		timeAlive := stats.timeAlive
+
+		if stats.snake != nil {
+			row.Snake = stats.snake.snake.GetUUID()
+			row.Length = stats.snake.length
+			row.Kills += stats.snake.snake.GetKills()
+			timeAlive += now.Sub(stats.snake.born)
+		}
+
+		row.TimeAlive = uint32(timeAlive / time.Second)
+		rows = append(rows, row)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Length != rows[j].Length {
+			return rows[i].Length > rows[j].Length
+		}
+		if rows[i].MaxLength != rows[j].MaxLength {
+			return rows[i].MaxLength > rows[j].MaxLength
+		}
+		if rows[i].Kills != rows[j].Kills {
+			return rows[i].Kills > rows[j].Kills
+		}
+		return rows[i].Player < rows[j].Player
+	})
+
+	return rows
+}
+
+// Scoreboard returns statistics of players in game
+func (g *Game) Scoreboard() []ScoreboardRow {
+	return g.scoreboard.list(time.Now())
+}
+
+// publishScoreboard pushes scoreboard to listeners once per scoreboard interval
+func (g *Game) publishScoreboard(stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(scoreboardInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if rows := g.Scoreboard(); len(rows) > 0 {
+					g.publish(Event{
+						Type:    EventTypeScoreboard,
+						Payload: rows,
+					})
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
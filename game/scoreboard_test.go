@@ -0,0 +1,126 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/profile"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+func Test_scoreboard_CountsDeathsAndTimeAliveByPlayer(t *testing.T) {
+	w, err := world.NewWorld(50, 50)
+	require.Nil(t, err)
+
+	p, err := profile.NewProfile("Ivan", "", 0)
+	require.Nil(t, err)
+	p.ID = "player"
+
+	sb := newScoreboard()
+	start := time.Now()
+
+	first, err := snake.NewSnake(w, p, nil)
+	require.Nil(t, err)
+	sb.update(first, start)
+	sb.update(first, start.Add(time.Second))
+	sb.delete(first, first.Death(), start.Add(time.Second*10))
+
+	rows := sb.list(start.Add(time.Second * 10))
+	require.Len(t, rows, 1)
+	require.Equal(t, "player", rows[0].Player)
+	require.Equal(t, "Ivan", rows[0].Nickname)
+	require.Empty(t, rows[0].Snake)
+	require.Equal(t, uint16(0), rows[0].Length)
+	require.Equal(t, first.GetLength(), rows[0].MaxLength)
+	require.Equal(t, uint16(1), rows[0].Deaths)
+	require.Equal(t, uint32(10), rows[0].TimeAlive)
+
+	second, err := snake.NewSnake(w, p, nil)
+	require.Nil(t, err)
+	sb.update(second, start.Add(time.Second*20))
+
+	rows = sb.list(start.Add(time.Second * 25))
+	require.Len(t, rows, 1, "snakes of the same player share a row")
+	require.Equal(t, second.GetUUID(), rows[0].Snake)
+	require.Equal(t, second.GetLength(), rows[0].Length)
+	require.Equal(t, uint32(15), rows[0].TimeAlive)
+
+	sb.delete(second, second.Death(), start.Add(time.Second*30))
+	sb.delete(second, second.Death(), start.Add(time.Second*40))
+
+	rows = sb.list(start.Add(time.Second * 40))
+	require.Equal(t, uint16(2), rows[0].Deaths, "repeated delete event is ignored")
+	require.Equal(t, uint32(20), rows[0].TimeAlive)
+}
+
+func Test_scoreboard_SortsRowsByLength(t *testing.T) {
+	w, err := world.NewWorld(50, 50)
+	require.Nil(t, err)
+
+	sb := newScoreboard()
+	now := time.Now()
+
+	dead, err := snake.NewSnake(w, profile.NewDefaultProfile(), nil)
+	require.Nil(t, err)
+	sb.update(dead, now)
+	sb.delete(dead, dead.Death(), now)
+
+	alive, err := snake.NewSnake(w, profile.NewDefaultProfile(), nil)
+	require.Nil(t, err)
+	sb.update(alive, now)
+
+	rows := sb.list(now)
+	require.Len(t, rows, 2, "players without sessions are identified by snakes")
+	require.Equal(t, alive.GetUUID(), rows[0].Player)
+	require.Equal(t, dead.GetUUID(), rows[1].Player)
+}
+
+func Test_scoreboard_MarksBotsAndSkipsTheirResults(t *testing.T) {
+	w, err := world.NewWorld(50, 50)
+	require.Nil(t, err)
+
+	p, err := profile.NewProfile("bot 1", "", 0)
+	require.Nil(t, err)
+	p.ID = "bot"
+	p.Bot = true
+
+	sb := newScoreboard()
+	now := time.Now()
+
+	s, err := snake.NewSnake(w, p, nil)
+	require.Nil(t, err)
+	sb.update(s, now)
+
+	rows := sb.list(now)
+	require.Len(t, rows, 1)
+	require.True(t, rows[0].Bot)
+
+	_, ok := sb.delete(s, s.Death(), now.Add(time.Second))
+	require.False(t, ok, "results of bots are not recorded to leaderboard")
+
+	rows = sb.list(now.Add(time.Second))
+	require.Equal(t, uint16(1), rows[0].Deaths)
+}
+
+func Test_scoreboard_SkipsResultsOfSnakesRemovedOnReset(t *testing.T) {
+	w, err := world.NewWorld(50, 50)
+	require.Nil(t, err)
+
+	p, err := profile.NewProfile("Ivan", "", 0)
+	require.Nil(t, err)
+	p.ID = "player"
+
+	sb := newScoreboard()
+	now := time.Now()
+
+	s, err := snake.NewSnake(w, p, nil)
+	require.Nil(t, err)
+	sb.update(s, now)
+
+	_, ok := sb.delete(s, snake.Death{Cause: snake.DeathCauseReset}, now)
+	require.False(t, ok, "reset is not a game played")
+	require.Equal(t, uint16(0), sb.list(now)[0].Deaths)
+}
@@ -0,0 +1,37 @@
+package game
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/objects/registry"
+)
+
+// restoreSnapshot creates objects of configured snapshot on map
+func (g *Game) restoreSnapshot() {
+	for _, s := range g.snapshot {
+		if _, err := registry.RestoreSnapshot(g.world.Done(), g.world, s); err != nil {
+			g.logger.WithFields(logrus.Fields{
+				"type": s.Type,
+			}).WithError(err).Error("cannot restore object from snapshot")
+		}
+	}
+}
+
+// Snapshot returns snapshots of objects on map which can be restored. Snakes and objects of game modes are skipped
+func (g *Game) Snapshot() ([]*registry.Snapshot, error) {
+	snapshot := make([]*registry.Snapshot, 0)
+
+	for _, object := range g.world.GetObjects() {
+		if t, ok := registry.TypeOf(object); !ok || !t.Restorable() {
+			continue
+		}
+
+		s, err := registry.TakeSnapshot(object)
+		if err != nil {
+			return nil, err
+		}
+		snapshot = append(snapshot, s)
+	}
+
+	return snapshot, nil
+}
@@ -0,0 +1,74 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects/registry"
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/objects/wall"
+	"github.com/ivan1993spb/snake-server/profile"
+)
+
+func Test_Game_restoreSnapshot_RestoresObjectsOnReset(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	g, err := NewGame(logger, Config{
+		Width:   20,
+		Height:  20,
+		Objects: []string{},
+		Snapshot: []*registry.Snapshot{
+			{
+				Type: "wall",
+				Data: json.RawMessage(`[[1,2],[1,3]]`),
+			},
+		},
+	})
+	require.Nil(t, err)
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	g.world.Start(stop)
+	g.restoreSnapshot()
+	g.startObservers(stop)
+
+	require.IsType(t, &wall.Wall{}, g.world.GetObjectByDot(engine.Dot{X: 1, Y: 2}))
+
+	_, err = snake.NewSnake(g.world, &profile.Profile{}, nil)
+	require.Nil(t, err)
+
+	snapshot, err := g.Snapshot()
+	require.Nil(t, err)
+	require.Len(t, snapshot, 1, "snakes are not saved to snapshot")
+	require.Equal(t, "wall", snapshot[0].Type)
+
+	g.reset(stop)
+
+	require.IsType(t, &wall.Wall{}, g.world.GetObjectByDot(engine.Dot{X: 1, Y: 3}))
+	require.Len(t, g.world.GetObjects(), 1)
+}
+
+func Test_NewGame_ReturnsErrorOnInvalidSnapshot(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	for _, s := range []*registry.Snapshot{
+		{Type: "unknown"},
+		{Type: "snake"},
+		{Type: "wall", Data: json.RawMessage(`[[30,30]]`)},
+		{Type: "wall", Data: json.RawMessage(`"wall"`)},
+	} {
+		_, err := NewGame(logger, Config{
+			Width:    20,
+			Height:   20,
+			Objects:  []string{},
+			Snapshot: []*registry.Snapshot{s},
+		})
+		require.IsType(t, &ErrCreateGame{}, err, s.Type)
+		require.IsType(t, &ErrInvalidSnapshot{}, err.(*ErrCreateGame).Err, s.Type)
+	}
+}
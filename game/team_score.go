@@ -0,0 +1,48 @@
+package game
+
+// Each kill adds teamKillScore points to team score
+const teamKillScore = 10
+
+// TeamScore is aggregated from lengths of team snakes and team kills
+type TeamScore struct {
+	Name    string `json:"name"`
+	Color   string `json:"color"`
+	Members int    `json:"members"`
+	Length  uint16 `json:"length"`
+	Kills   uint16 `json:"kills"`
+	Score   uint32 `json:"score"`
+}
+
+// TeamScores returns scores of teams or nil if game has no teams
+func (g *Game) TeamScores() []TeamScore {
+	if g.teams == nil {
+		return nil
+	}
+
+	list := g.teams.List()
+	scores := make([]TeamScore, len(list))
+
+	for i, team := range list {
+		scores[i] = TeamScore{
+			Name:    team.Name(),
+			Color:   team.Color(),
+			Members: team.Members(),
+			Kills:   team.Kills(),
+		}
+	}
+
+	for _, s := range snakes(g.world) {
+		team := s.GetTeam()
+		for i := range list {
+			if list[i] == team {
+				scores[i].Length += s.GetLength()
+			}
+		}
+	}
+
+	for i := range scores {
+		scores[i].Score = uint32(scores[i].Length) + uint32(scores[i].Kills)*teamKillScore
+	}
+
+	return scores
+}
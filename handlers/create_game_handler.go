@@ -3,11 +3,20 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/ivan1993spb/snake-server/bot"
+	"github.com/ivan1993spb/snake-server/chat"
 	"github.com/ivan1993spb/snake-server/connections"
+	"github.com/ivan1993spb/snake-server/game"
+	"github.com/ivan1993spb/snake-server/objects/registry"
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/player"
 )
 
 const URLRouteCreateGame = "/games"
@@ -16,15 +25,72 @@ const MethodCreateGame = http.MethodPost
 
 const (
 	postFieldConnectionLimit = "limit"
+	postFieldSpectatorLimit  = "spectators"
 	postFieldMapWidth        = "width"
 	postFieldMapHeight       = "height"
+	postFieldObjects         = "objects"
+	postFieldMode            = "mode"
+	postFieldTeams           = "teams"
+	postFieldFriendlyFire    = "friendly_fire"
+	postFieldLobby           = "lobby"
+	postFieldMinPlayers      = "min_players"
+	postFieldCountdown       = "countdown"
+	postFieldResults         = "results"
+	postFieldRespawn         = "respawn"
+	postFieldRespawnDelay    = "respawn_delay"
+	postFieldLives           = "lives"
+	postFieldSpawnProtection = "spawn_protection"
+	postFieldSpawnDistance   = "spawn_distance"
+	postFieldTurnQueue       = "turn_queue"
+	postFieldTurnOverflow    = "turn_overflow"
+	postFieldReconnectGrace  = "reconnect_grace"
+	postFieldReconnectFreeze = "reconnect_freeze"
+	postFieldIdleTimeout     = "idle_timeout"
+	postFieldIdleWarning     = "idle_warning"
+	postFieldIdleAction      = "idle_action"
+	postFieldChatRate        = "chat_rate"
+	postFieldChatInterval    = "chat_interval"
+	postFieldChatMaxLength   = "chat_max_length"
+	postFieldChatBlocklist   = "chat_blocklist"
+	postFieldChatDuplicates  = "chat_duplicate_window"
+	postFieldBots            = "bots"
+	postFieldBotDifficulty   = "bot_difficulty"
+	postFieldSnapshot        = "snapshot"
+)
+
+// Values of respawn field
+const (
+	respawnAuto   = "auto"
+	respawnManual = "manual"
+)
+
+// Default limit of spectator connections
+const defaultSpectatorLimit = 10
+
+// Defaults of round lifecycle
+const (
+	defaultMinPlayers = 2
+	defaultCountdown  = time.Second * 5
+	defaultResults    = time.Second * 10
+)
+
+const (
+	objectsSeparator      = ","
+	objectOptionSeparator = "."
+	blocklistSeparator    = ","
+	difficultiesSeparator = ","
 )
 
 type responseCreateGameHandler struct {
-	ID     int   `json:"id"`
-	Limit  int   `json:"limit"`
-	Width  uint8 `json:"width"`
-	Height uint8 `json:"height"`
+	ID         int      `json:"id"`
+	Limit      int      `json:"limit"`
+	Spectators int      `json:"spectator_limit"`
+	Width      uint8    `json:"width"`
+	Height     uint8    `json:"height"`
+	Objects    []string `json:"objects"`
+	Mode       string   `json:"mode"`
+	Teams      []string `json:"teams,omitempty"`
+	Lobby      bool     `json:"lobby"`
 }
 
 type responseCreateGameHandlerError struct {
@@ -35,6 +101,7 @@ type responseCreateGameHandlerError struct {
 type createGameHandler struct {
 	logger       logrus.FieldLogger
 	groupManager *connections.ConnectionGroupManager
+	recorder     game.Recorder
 }
 
 type ErrCreateGameHandler string
@@ -43,10 +110,14 @@ func (e ErrCreateGameHandler) Error() string {
 	return "create game handler error: " + string(e)
 }
 
-func NewCreateGameHandler(logger logrus.FieldLogger, groupManager *connections.ConnectionGroupManager) http.Handler {
+// NewCreateGameHandler returns handler which creates games. Recorder saves results of snakes of created games, it
+// may be nil
+func NewCreateGameHandler(logger logrus.FieldLogger, groupManager *connections.ConnectionGroupManager,
+	recorder game.Recorder) http.Handler {
 	return &createGameHandler{
 		logger:       logger,
 		groupManager: groupManager,
+		recorder:     recorder,
 	}
 }
 
@@ -69,6 +140,18 @@ func (h *createGameHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	spectatorLimit := defaultSpectatorLimit
+	if value := r.PostFormValue(postFieldSpectatorLimit); value != "" {
+		if spectatorLimit, err = strconv.Atoi(value); err != nil || spectatorLimit < 0 {
+			h.logger.Warnln(ErrCreateGameHandler("invalid spectator limit"), value)
+			h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateGameHandlerError{
+				Code: http.StatusBadRequest,
+				Text: "invalid spectators",
+			})
+			return
+		}
+	}
+
 	mapWidth, err := strconv.ParseUint(r.PostFormValue(postFieldMapWidth), 10, 8)
 	if err != nil {
 		h.logger.Error(ErrCreateGameHandler(err.Error()))
@@ -105,13 +188,235 @@ func (h *createGameHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var teamsCount int
+	if value := r.PostFormValue(postFieldTeams); value != "" {
+		if teamsCount, err = strconv.Atoi(value); err != nil {
+			h.logger.Error(ErrCreateGameHandler(err.Error()))
+			h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateGameHandlerError{
+				Code: http.StatusBadRequest,
+				Text: "invalid teams",
+			})
+			return
+		}
+	}
+
+	friendlyFire := true
+	if value := r.PostFormValue(postFieldFriendlyFire); value != "" {
+		if friendlyFire, err = strconv.ParseBool(value); err != nil {
+			h.logger.Error(ErrCreateGameHandler(err.Error()))
+			h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateGameHandlerError{
+				Code: http.StatusBadRequest,
+				Text: "invalid friendly_fire",
+			})
+			return
+		}
+	}
+
+	lobby := false
+	if value := r.PostFormValue(postFieldLobby); value != "" {
+		if lobby, err = strconv.ParseBool(value); err != nil {
+			h.logger.Error(ErrCreateGameHandler(err.Error()))
+			h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateGameHandlerError{
+				Code: http.StatusBadRequest,
+				Text: "invalid lobby",
+			})
+			return
+		}
+	}
+
+	minPlayers := defaultMinPlayers
+	if value := r.PostFormValue(postFieldMinPlayers); value != "" {
+		if minPlayers, err = strconv.Atoi(value); err != nil {
+			h.logger.Error(ErrCreateGameHandler(err.Error()))
+			h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateGameHandlerError{
+				Code: http.StatusBadRequest,
+				Text: "invalid min_players",
+			})
+			return
+		}
+	}
+
+	countdown := defaultCountdown
+	if value := r.PostFormValue(postFieldCountdown); value != "" {
+		if countdown, err = time.ParseDuration(value); err != nil {
+			h.logger.Error(ErrCreateGameHandler(err.Error()))
+			h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateGameHandlerError{
+				Code: http.StatusBadRequest,
+				Text: "invalid countdown",
+			})
+			return
+		}
+	}
+
+	results := defaultResults
+	if value := r.PostFormValue(postFieldResults); value != "" {
+		if results, err = time.ParseDuration(value); err != nil {
+			h.logger.Error(ErrCreateGameHandler(err.Error()))
+			h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateGameHandlerError{
+				Code: http.StatusBadRequest,
+				Text: "invalid results",
+			})
+			return
+		}
+	}
+
+	respawn, text := h.parseRespawn(r)
+	if text != "" {
+		h.logger.Warn(ErrCreateGameHandler(text))
+		h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateGameHandlerError{
+			Code: http.StatusBadRequest,
+			Text: text,
+		})
+		return
+	}
+
+	reconnect, text := h.parseReconnect(r)
+	if text != "" {
+		h.logger.Warn(ErrCreateGameHandler(text))
+		h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateGameHandlerError{
+			Code: http.StatusBadRequest,
+			Text: text,
+		})
+		return
+	}
+
+	idle, text := h.parseIdle(r)
+	if text != "" {
+		h.logger.Warn(ErrCreateGameHandler(text))
+		h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateGameHandlerError{
+			Code: http.StatusBadRequest,
+			Text: text,
+		})
+		return
+	}
+
+	chatConfig, text := h.parseChat(r)
+	if text != "" {
+		h.logger.Warn(ErrCreateGameHandler(text))
+		h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateGameHandlerError{
+			Code: http.StatusBadRequest,
+			Text: text,
+		})
+		return
+	}
+
+	var bots bot.Config
+	if value := r.PostFormValue(postFieldBots); value != "" {
+		if bots.Target, err = strconv.Atoi(value); err != nil {
+			h.logger.Warn(ErrCreateGameHandler(err.Error()))
+			h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateGameHandlerError{
+				Code: http.StatusBadRequest,
+				Text: "invalid bots",
+			})
+			return
+		}
+		// Bots are players of game and cannot exceed player limit
+		if bots.Target > connectionLimit {
+			h.logger.Warnln(ErrCreateGameHandler("bots exceed connection limit"), bots.Target)
+			h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateGameHandlerError{
+				Code: http.StatusBadRequest,
+				Text: "invalid bots",
+			})
+			return
+		}
+	}
+	if value := strings.TrimSpace(r.PostFormValue(postFieldBotDifficulty)); value != "" {
+		for _, label := range strings.Split(value, difficultiesSeparator) {
+			difficulty, err := bot.ParseDifficulty(strings.TrimSpace(label))
+			if err != nil {
+				h.logger.Warn(ErrCreateGameHandler(err.Error()))
+				h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateGameHandlerError{
+					Code: http.StatusBadRequest,
+					Text: "invalid bots",
+				})
+				return
+			}
+			bots.Difficulties = append(bots.Difficulties, difficulty)
+		}
+	}
+
+	var snapshot []*registry.Snapshot
+	if value := r.PostFormValue(postFieldSnapshot); value != "" {
+		if err := json.Unmarshal([]byte(value), &snapshot); err != nil {
+			h.logger.Warn(ErrCreateGameHandler(err.Error()))
+			h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateGameHandlerError{
+				Code: http.StatusBadRequest,
+				Text: "invalid snapshot",
+			})
+			return
+		}
+	}
+
+	config := game.Config{
+		Width:         uint8(mapWidth),
+		Height:        uint8(mapHeight),
+		Objects:       h.parseObjects(r.PostFormValue(postFieldObjects)),
+		ObjectOptions: h.parseObjectOptions(r.PostForm),
+		Snapshot:      snapshot,
+		Mode:          r.PostFormValue(postFieldMode),
+		ModeOptions:   h.parseModeOptions(r.PostForm),
+		Teams:         teamsCount,
+		FriendlyFire:  friendlyFire,
+		Lobby:         lobby,
+		MinPlayers:    minPlayers,
+		Countdown:     countdown,
+		Results:       results,
+		Respawn:       respawn,
+		Reconnect:     reconnect,
+		Idle:          idle,
+		Chat:          chatConfig,
+		Bots:          bots,
+		Recorder:      h.recorder,
+	}
+
+	if err := config.Validate(); err != nil {
+		h.logger.Warn(ErrCreateGameHandler(err.Error()))
+		text := "invalid objects"
+		switch err.(type) {
+		case *game.ErrInvalidObjectOptions:
+			text = "invalid object options"
+		case *game.ErrUnknownMode:
+			text = "invalid mode"
+		case *game.ErrInvalidModeOptions:
+			text = "invalid mode options"
+		case *game.ErrInvalidTeams:
+			text = "invalid teams"
+		case *game.ErrInvalidLobby:
+			text = "invalid lobby"
+		case player.ErrInvalidRespawn:
+			text = "invalid respawn"
+		case player.ErrInvalidReconnect:
+			text = "invalid reconnect"
+		case snake.ErrInvalidTurnQueue:
+			text = "invalid turn queue"
+		case game.ErrInvalidIdle:
+			text = "invalid idle"
+		case chat.ErrInvalidConfig:
+			text = "invalid chat"
+		case bot.ErrInvalidConfig:
+			text = "invalid bots"
+		case *game.ErrInvalidSnapshot:
+			text = "invalid snapshot"
+		}
+		h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateGameHandlerError{
+			Code: http.StatusBadRequest,
+			Text: text,
+		})
+		return
+	}
+
 	h.logger.WithFields(logrus.Fields{
 		"width":            mapWidth,
 		"height":           mapHeight,
 		"connection_limit": connectionLimit,
+		"objects":          config.Objects,
+		"mode":             config.Mode,
+		"teams":            config.Teams,
+		"friendly_fire":    config.FriendlyFire,
+		"lobby":            config.Lobby,
 	}).Debug("create game group")
 
-	group, err := connections.NewConnectionGroup(h.logger, connectionLimit, uint8(mapWidth), uint8(mapHeight))
+	group, err := connections.NewConnectionGroup(h.logger, connectionLimit, spectatorLimit, config)
 	if err != nil {
 		h.logger.Error(ErrCreateGameHandler(err.Error()))
 		h.writeResponseJSON(w, http.StatusInternalServerError, &responseCreateGameHandlerError{
@@ -151,13 +456,239 @@ func (h *createGameHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h.logger.WithField("group_id", id).Infoln("created group")
 
 	h.writeResponseJSON(w, http.StatusCreated, &responseCreateGameHandler{
-		ID:     id,
-		Limit:  group.GetLimit(),
-		Width:  uint8(mapWidth),
-		Height: uint8(mapHeight),
+		ID:         id,
+		Limit:      group.GetLimit(),
+		Spectators: group.GetSpectatorLimit(),
+		Width:      uint8(mapWidth),
+		Height:     uint8(mapHeight),
+		Objects:    group.GetObjects(),
+		Mode:       group.GetMode(),
+		Teams:      group.GetTeams(),
+		Lobby:      group.GetLobby(),
 	})
 }
 
+// parseRespawn reads respawn settings of players. It returns error text if a field is invalid
+func (h *createGameHandler) parseRespawn(r *http.Request) (player.Respawn, string) {
+	respawn := player.DefaultRespawn()
+
+	switch r.PostFormValue(postFieldRespawn) {
+	case "", respawnAuto:
+	case respawnManual:
+		respawn.Manual = true
+	default:
+		return respawn, "invalid respawn"
+	}
+
+	if value := r.PostFormValue(postFieldRespawnDelay); value != "" {
+		delay, err := time.ParseDuration(value)
+		if err != nil {
+			return respawn, "invalid respawn_delay"
+		}
+		respawn.Delay = delay
+	}
+
+	if value := r.PostFormValue(postFieldLives); value != "" {
+		lives, err := strconv.Atoi(value)
+		if err != nil {
+			return respawn, "invalid lives"
+		}
+		respawn.Lives = lives
+	}
+
+	if value := r.PostFormValue(postFieldSpawnProtection); value != "" {
+		protection, err := time.ParseDuration(value)
+		if err != nil {
+			return respawn, "invalid spawn_protection"
+		}
+		respawn.Protection = protection
+	}
+
+	if value := r.PostFormValue(postFieldSpawnDistance); value != "" {
+		distance, err := strconv.ParseUint(value, 10, 8)
+		if err != nil {
+			return respawn, "invalid spawn_distance"
+		}
+		respawn.SafeDistance = uint8(distance)
+	}
+
+	if value := r.PostFormValue(postFieldTurnQueue); value != "" {
+		depth, err := strconv.Atoi(value)
+		if err != nil {
+			return respawn, "invalid turn_queue"
+		}
+		respawn.Turns.Depth = depth
+	}
+
+	if value := r.PostFormValue(postFieldTurnOverflow); value != "" {
+		overflow, err := snake.ParseTurnOverflow(value)
+		if err != nil {
+			return respawn, "invalid turn_overflow"
+		}
+		respawn.Turns.Overflow = overflow
+	}
+
+	return respawn, ""
+}
+
+// parseReconnect reads settings of keeping snakes of disconnected players. It returns error text if a field is invalid
+func (h *createGameHandler) parseReconnect(r *http.Request) (player.Reconnect, string) {
+	reconnect := player.DefaultReconnect()
+
+	if value := r.PostFormValue(postFieldReconnectGrace); value != "" {
+		grace, err := time.ParseDuration(value)
+		if err != nil {
+			return reconnect, "invalid reconnect_grace"
+		}
+		reconnect.Grace = grace
+	}
+
+	if value := r.PostFormValue(postFieldReconnectFreeze); value != "" {
+		freeze, err := strconv.ParseBool(value)
+		if err != nil {
+			return reconnect, "invalid reconnect_freeze"
+		}
+		reconnect.Freeze = freeze
+	}
+
+	return reconnect, ""
+}
+
+// parseIdle reads settings of detection of idle players. It returns error text if a field is invalid
+func (h *createGameHandler) parseIdle(r *http.Request) (game.Idle, string) {
+	idle := game.DefaultIdle()
+
+	if value := r.PostFormValue(postFieldIdleTimeout); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return idle, "invalid idle_timeout"
+		}
+		idle.Timeout = timeout
+	}
+
+	if value := r.PostFormValue(postFieldIdleWarning); value != "" {
+		warning, err := time.ParseDuration(value)
+		if err != nil {
+			return idle, "invalid idle_warning"
+		}
+		idle.Warning = warning
+	}
+
+	if value := r.PostFormValue(postFieldIdleAction); value != "" {
+		action, err := game.ParseIdleAction(value)
+		if err != nil {
+			return idle, "invalid idle_action"
+		}
+		idle.Action = action
+	}
+
+	return idle, ""
+}
+
+// parseChat reads settings of chat moderation. It returns error text if a field is invalid
+func (h *createGameHandler) parseChat(r *http.Request) (chat.Config, string) {
+	config := chat.DefaultConfig()
+
+	if value := r.PostFormValue(postFieldChatRate); value != "" {
+		rate, err := strconv.Atoi(value)
+		if err != nil {
+			return config, "invalid chat_rate"
+		}
+		config.Rate = rate
+	}
+
+	if value := r.PostFormValue(postFieldChatInterval); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil {
+			return config, "invalid chat_interval"
+		}
+		config.Interval = interval
+	}
+
+	if value := r.PostFormValue(postFieldChatMaxLength); value != "" {
+		maxLength, err := strconv.Atoi(value)
+		if err != nil {
+			return config, "invalid chat_max_length"
+		}
+		config.MaxLength = maxLength
+	}
+
+	if value := r.PostFormValue(postFieldChatDuplicates); value != "" {
+		window, err := time.ParseDuration(value)
+		if err != nil {
+			return config, "invalid chat_duplicate_window"
+		}
+		config.DuplicateWindow = window
+	}
+
+	if value := strings.TrimSpace(r.PostFormValue(postFieldChatBlocklist)); value != "" {
+		for _, word := range strings.Split(value, blocklistSeparator) {
+			if word = strings.TrimSpace(word); word != "" {
+				config.Blocklist = append(config.Blocklist, word)
+			}
+		}
+	}
+
+	return config, ""
+}
+
+// parseObjects parses comma separated list of object type labels
+func (h *createGameHandler) parseObjects(value string) []string {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+
+	labels := strings.Split(value, objectsSeparator)
+	objects := make([]string, 0, len(labels))
+
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if !containsString(objects, label) {
+			objects = append(objects, label)
+		}
+	}
+
+	return objects
+}
+
+// parseObjectOptions collects fields named as "type.option" to options of object types
+func (h *createGameHandler) parseObjectOptions(form url.Values) map[string]registry.Options {
+	objectOptions := map[string]registry.Options{}
+
+	for field, values := range form {
+		parts := strings.SplitN(field, objectOptionSeparator, 2)
+		if len(parts) != 2 || len(values) == 0 {
+			continue
+		}
+
+		label, option := parts[0], parts[1]
+		if label == postFieldMode {
+			continue
+		}
+		if _, ok := objectOptions[label]; !ok {
+			objectOptions[label] = registry.Options{}
+		}
+		objectOptions[label][option] = values[0]
+	}
+
+	return objectOptions
+}
+
+// parseModeOptions collects fields named as "mode.option" to options of game mode
+func (h *createGameHandler) parseModeOptions(form url.Values) game.ModeOptions {
+	modeOptions := game.ModeOptions{}
+
+	for field, values := range form {
+		parts := strings.SplitN(field, objectOptionSeparator, 2)
+		if len(parts) != 2 || parts[0] != postFieldMode || len(values) == 0 {
+			continue
+		}
+		modeOptions[parts[1]] = values[0]
+	}
+
+	return modeOptions
+}
+
 func (h *createGameHandler) writeResponseJSON(w http.ResponseWriter, statusCode int, response interface{}) {
 	w.WriteHeader(statusCode)
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -166,3 +697,12 @@ func (h *createGameHandler) writeResponseJSON(w http.ResponseWriter, statusCode
 		h.logger.Error(ErrCreateGameHandler(err.Error()))
 	}
 }
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
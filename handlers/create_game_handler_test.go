@@ -25,7 +25,7 @@ func Test_CreateGameHandler_ServeHTTP_CreatesGroup(t *testing.T) {
 	require.Nil(t, err)
 	require.NotNil(t, groupManager)
 
-	handler := NewCreateGameHandler(logger, groupManager)
+	handler := NewCreateGameHandler(logger, groupManager, nil)
 
 	r := mux.NewRouter()
 	r.Path(URLRouteCreateGame).Methods(MethodCreateGame).Handler(handler)
@@ -55,3 +55,324 @@ func Test_CreateGameHandler_ServeHTTP_CreatesGroup(t *testing.T) {
 
 	hook.Reset()
 }
+
+func Test_CreateGameHandler_ServeHTTP_ValidatesObjects(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	groupManager, err := connections.NewConnectionGroupManager(logger, 5, 10)
+	require.Nil(t, err)
+
+	handler := NewCreateGameHandler(logger, groupManager, nil)
+
+	data := &url.Values{}
+	data.Add(postFieldConnectionLimit, "5")
+	data.Add(postFieldMapWidth, "50")
+	data.Add(postFieldMapHeight, "50")
+	data.Add(postFieldObjects, "apple,unknown")
+
+	request := httptest.NewRequest(MethodCreateGame, URLRouteCreateGame, strings.NewReader(data.Encode()))
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Len(t, groupManager.Groups(), 0)
+
+	data.Set(postFieldObjects, "apple, wall,apple")
+
+	request = httptest.NewRequest(MethodCreateGame, URLRouteCreateGame, strings.NewReader(data.Encode()))
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+
+	group, err := groupManager.Get(0)
+	require.Nil(t, err)
+	require.Equal(t, []string{"apple", "wall"}, group.GetObjects())
+	group.Stop()
+	require.Nil(t, groupManager.Delete(group))
+
+	hook.Reset()
+}
+
+func Test_CreateGameHandler_ServeHTTP_ValidatesObjectOptions(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	groupManager, err := connections.NewConnectionGroupManager(logger, 5, 10)
+	require.Nil(t, err)
+
+	handler := NewCreateGameHandler(logger, groupManager, nil)
+
+	data := &url.Values{}
+	data.Add(postFieldConnectionLimit, "5")
+	data.Add(postFieldMapWidth, "50")
+	data.Add(postFieldMapHeight, "50")
+	data.Add("apple.strategy", "unknown")
+
+	request := httptest.NewRequest(MethodCreateGame, URLRouteCreateGame, strings.NewReader(data.Encode()))
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "invalid object options")
+	require.Len(t, groupManager.Groups(), 0)
+
+	hook.Reset()
+}
+
+func Test_CreateGameHandler_ServeHTTP_ValidatesMode(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	groupManager, err := connections.NewConnectionGroupManager(logger, 5, 10)
+	require.Nil(t, err)
+
+	handler := NewCreateGameHandler(logger, groupManager, nil)
+
+	data := &url.Values{}
+	data.Add(postFieldConnectionLimit, "5")
+	data.Add(postFieldMapWidth, "50")
+	data.Add(postFieldMapHeight, "50")
+	data.Add(postFieldMode, "deathmatch")
+	data.Add("mode.duration", "forever")
+
+	request := httptest.NewRequest(MethodCreateGame, URLRouteCreateGame, strings.NewReader(data.Encode()))
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "invalid mode options")
+	require.Len(t, groupManager.Groups(), 0)
+
+	data.Set("mode.duration", "2m")
+
+	request = httptest.NewRequest(MethodCreateGame, URLRouteCreateGame, strings.NewReader(data.Encode()))
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+
+	group, err := groupManager.Get(0)
+	require.Nil(t, err)
+	require.Equal(t, "deathmatch", group.GetMode())
+	group.Stop()
+	require.Nil(t, groupManager.Delete(group))
+
+	hook.Reset()
+}
+
+func Test_CreateGameHandler_ServeHTTP_CreatesTeams(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	groupManager, err := connections.NewConnectionGroupManager(logger, 5, 10)
+	require.Nil(t, err)
+
+	handler := NewCreateGameHandler(logger, groupManager, nil)
+
+	data := &url.Values{}
+	data.Add(postFieldConnectionLimit, "5")
+	data.Add(postFieldMapWidth, "50")
+	data.Add(postFieldMapHeight, "50")
+	data.Add(postFieldTeams, "5")
+
+	request := httptest.NewRequest(MethodCreateGame, URLRouteCreateGame, strings.NewReader(data.Encode()))
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "invalid teams")
+
+	data.Set(postFieldTeams, "2")
+	data.Set(postFieldFriendlyFire, "false")
+
+	request = httptest.NewRequest(MethodCreateGame, URLRouteCreateGame, strings.NewReader(data.Encode()))
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+
+	group, err := groupManager.Get(0)
+	require.Nil(t, err)
+	require.Equal(t, []string{"red", "blue"}, group.GetTeams())
+	group.Stop()
+	require.Nil(t, groupManager.Delete(group))
+
+	hook.Reset()
+}
+
+func Test_CreateGameHandler_ServeHTTP_CreatesLobby(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	groupManager, err := connections.NewConnectionGroupManager(logger, 5, 10)
+	require.Nil(t, err)
+
+	handler := NewCreateGameHandler(logger, groupManager, nil)
+
+	data := &url.Values{}
+	data.Add(postFieldConnectionLimit, "5")
+	data.Add(postFieldMapWidth, "50")
+	data.Add(postFieldMapHeight, "50")
+	data.Add(postFieldLobby, "true")
+	data.Add(postFieldMinPlayers, "0")
+
+	request := httptest.NewRequest(MethodCreateGame, URLRouteCreateGame, strings.NewReader(data.Encode()))
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "invalid lobby")
+
+	data.Set(postFieldMinPlayers, "3")
+	data.Set(postFieldCountdown, "3s")
+
+	request = httptest.NewRequest(MethodCreateGame, URLRouteCreateGame, strings.NewReader(data.Encode()))
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `"lobby":true`)
+
+	group, err := groupManager.Get(0)
+	require.Nil(t, err)
+	require.True(t, group.GetLobby())
+	group.Stop()
+	require.Nil(t, groupManager.Delete(group))
+
+	hook.Reset()
+}
+
+func Test_CreateGameHandler_ServeHTTP_ValidatesRespawn(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	groupManager, err := connections.NewConnectionGroupManager(logger, 5, 10)
+	require.Nil(t, err)
+
+	handler := NewCreateGameHandler(logger, groupManager, nil)
+
+	for field, value := range map[string]string{
+		postFieldRespawn:         "never",
+		postFieldRespawnDelay:    "-1s",
+		postFieldLives:           "-1",
+		postFieldSpawnProtection: "abc",
+		postFieldTurnQueue:       "20",
+		postFieldTurnOverflow:    "ignore",
+		postFieldIdleTimeout:     "-1s",
+		postFieldIdleWarning:     "abc",
+		postFieldIdleAction:      "ban",
+		postFieldChatRate:        "-1",
+		postFieldChatMaxLength:   "1000",
+		postFieldChatInterval:    "abc",
+		postFieldBots:            "6",
+		postFieldBotDifficulty:   "easy,godlike",
+	} {
+		data := &url.Values{}
+		data.Add(postFieldConnectionLimit, "5")
+		data.Add(postFieldMapWidth, "50")
+		data.Add(postFieldMapHeight, "50")
+		data.Add(field, value)
+
+		request := httptest.NewRequest(MethodCreateGame, URLRouteCreateGame, strings.NewReader(data.Encode()))
+		request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusBadRequest, recorder.Code, field)
+		require.Contains(t, recorder.Body.String(), "invalid", field)
+	}
+
+	data := &url.Values{}
+	data.Add(postFieldConnectionLimit, "5")
+	data.Add(postFieldMapWidth, "50")
+	data.Add(postFieldMapHeight, "50")
+	data.Add(postFieldRespawn, respawnManual)
+	data.Add(postFieldLives, "3")
+	data.Add(postFieldSpawnProtection, "2s")
+	data.Add(postFieldSpawnDistance, "4")
+	data.Add(postFieldTurnQueue, "2")
+	data.Add(postFieldTurnOverflow, "shift")
+	data.Add(postFieldIdleTimeout, "1m")
+	data.Add(postFieldIdleAction, "spectate")
+	data.Add(postFieldChatRate, "3")
+	data.Add(postFieldChatBlocklist, "bad, worse")
+	data.Add(postFieldBotDifficulty, "easy, insane")
+
+	request := httptest.NewRequest(MethodCreateGame, URLRouteCreateGame, strings.NewReader(data.Encode()))
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+
+	group, err := groupManager.Get(0)
+	require.Nil(t, err)
+	group.Stop()
+	require.Nil(t, groupManager.Delete(group))
+
+	hook.Reset()
+}
+
+func Test_CreateGameHandler_ServeHTTP_SetsSpectatorLimit(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	groupManager, err := connections.NewConnectionGroupManager(logger, 5, 10)
+	require.Nil(t, err)
+
+	handler := NewCreateGameHandler(logger, groupManager, nil)
+
+	data := &url.Values{}
+	data.Add(postFieldConnectionLimit, "10")
+	data.Add(postFieldMapWidth, "50")
+	data.Add(postFieldMapHeight, "50")
+	data.Add(postFieldSpectatorLimit, "-1")
+
+	request := httptest.NewRequest(MethodCreateGame, URLRouteCreateGame, strings.NewReader(data.Encode()))
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "invalid spectators")
+
+	data.Set(postFieldSpectatorLimit, "50")
+
+	request = httptest.NewRequest(MethodCreateGame, URLRouteCreateGame, strings.NewReader(data.Encode()))
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+	require.Contains(t, recorder.Body.String(), `"spectator_limit":50`)
+
+	group, err := groupManager.Get(0)
+	require.Nil(t, err)
+	require.Equal(t, 50, group.GetSpectatorLimit())
+	require.Equal(t, 10, group.GetLimit(), "spectators do not take connections of players")
+	require.False(t, group.IsSpectatorsFull())
+	group.Stop()
+	require.Nil(t, groupManager.Delete(group))
+
+	hook.Reset()
+}
+
+func Test_CreateGameHandler_ServeHTTP_RestoresSnapshot(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	groupManager, err := connections.NewConnectionGroupManager(logger, 5, 10)
+	require.Nil(t, err)
+
+	handler := NewCreateGameHandler(logger, groupManager, nil)
+
+	data := &url.Values{}
+	data.Add(postFieldConnectionLimit, "5")
+	data.Add(postFieldMapWidth, "20")
+	data.Add(postFieldMapHeight, "20")
+	data.Add(postFieldSnapshot, `[{"type":"wall","data":[[30,30]]}]`)
+
+	request := httptest.NewRequest(MethodCreateGame, URLRouteCreateGame, strings.NewReader(data.Encode()))
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "invalid snapshot")
+
+	data.Set(postFieldSnapshot, `[{"type":"wall","data":[[1,2],[1,3]]}]`)
+
+	request = httptest.NewRequest(MethodCreateGame, URLRouteCreateGame, strings.NewReader(data.Encode()))
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+
+	group, err := groupManager.Get(0)
+	require.Nil(t, err)
+	group.Stop()
+	require.Nil(t, groupManager.Delete(group))
+
+	hook.Reset()
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/sessions"
+)
+
+const URLRouteCreateSession = "/sessions"
+
+const MethodCreateSession = http.MethodPost
+
+type responseCreateSessionHandlerError struct {
+	Code int    `json:"code"`
+	Text string `json:"text"`
+}
+
+type createSessionHandler struct {
+	logger   logrus.FieldLogger
+	sessions *sessions.Store
+}
+
+type ErrCreateSessionHandler string
+
+func (e ErrCreateSessionHandler) Error() string {
+	return "create session handler error: " + string(e)
+}
+
+func NewCreateSessionHandler(logger logrus.FieldLogger, sessionStore *sessions.Store) http.Handler {
+	return &createSessionHandler{
+		logger:   logger,
+		sessions: sessionStore,
+	}
+}
+
+func (h *createSessionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.logger.Warn(ErrCreateSessionHandler(err.Error()))
+		h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateSessionHandlerError{
+			Code: http.StatusBadRequest,
+			Text: "invalid form",
+		})
+		return
+	}
+
+	p, err := parseProfile(r.Form)
+	if err != nil {
+		h.logger.Warn(ErrCreateSessionHandler(err.Error()))
+		h.writeResponseJSON(w, http.StatusBadRequest, &responseCreateSessionHandlerError{
+			Code: http.StatusBadRequest,
+			Text: err.Error(),
+		})
+		return
+	}
+
+	session, err := h.sessions.Create(p)
+	if err != nil {
+		h.logger.Error(ErrCreateSessionHandler(err.Error()))
+		h.writeResponseJSON(w, http.StatusInternalServerError, &responseCreateSessionHandlerError{
+			Code: http.StatusInternalServerError,
+			Text: "cannot create session",
+		})
+		return
+	}
+
+	h.logger.WithField("player_id", session.PlayerID()).Info("created session")
+
+	h.writeResponseJSON(w, http.StatusCreated, session)
+}
+
+func (h *createSessionHandler) writeResponseJSON(w http.ResponseWriter, statusCode int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error(ErrCreateSessionHandler(err.Error()))
+	}
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/sessions"
+)
+
+func Test_CreateSessionHandler_ServeHTTP_CreatesSession(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	store := sessions.NewStore()
+
+	r := mux.NewRouter()
+	r.Path(URLRouteCreateSession).Methods(MethodCreateSession).Handler(NewCreateSessionHandler(logger, store))
+
+	data := &url.Values{}
+	data.Add(queryFieldNickname, "Ivan")
+
+	request := httptest.NewRequest(MethodCreateSession, URLRouteCreateSession, strings.NewReader(data.Encode()))
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	recorder := httptest.NewRecorder()
+
+	r.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+
+	var response struct {
+		Token    string `json:"token"`
+		PlayerID string `json:"player_id"`
+	}
+	require.Nil(t, json.NewDecoder(recorder.Body).Decode(&response))
+
+	session, err := store.Get(response.Token)
+	require.Nil(t, err)
+	require.Equal(t, response.PlayerID, session.PlayerID())
+	require.Equal(t, "Ivan", session.Profile().Nickname)
+
+	hook.Reset()
+}
+
+func Test_CreateSessionHandler_ServeHTTP_RejectsInvalidProfile(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	store := sessions.NewStore()
+
+	r := mux.NewRouter()
+	r.Path(URLRouteCreateSession).Methods(MethodCreateSession).Handler(NewCreateSessionHandler(logger, store))
+
+	data := &url.Values{}
+	data.Add(queryFieldColor, "not a color")
+
+	request := httptest.NewRequest(MethodCreateSession, URLRouteCreateSession, strings.NewReader(data.Encode()))
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	recorder := httptest.NewRecorder()
+
+	r.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Equal(t, 0, store.Count())
+
+	hook.Reset()
+}
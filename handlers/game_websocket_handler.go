@@ -3,6 +3,7 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/gorilla/mux"
@@ -10,6 +11,8 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/ivan1993spb/snake-server/connections"
+	"github.com/ivan1993spb/snake-server/profile"
+	"github.com/ivan1993spb/snake-server/sessions"
 )
 
 const URLRouteGameWebSocketByID = "/games/{id}/ws"
@@ -18,6 +21,16 @@ const MethodGame = http.MethodGet
 
 const wsReadMessageLimit = 128
 
+const (
+	queryFieldNickname = "nickname"
+	queryFieldColor    = "color"
+	queryFieldSkin     = "skin"
+	queryFieldTeam     = "team"
+	queryFieldToken    = "token"
+	queryFieldSpectate = "spectate"
+	queryFieldChat     = "chat"
+)
+
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:    1024,
 	WriteBufferSize:   1024,
@@ -35,6 +48,7 @@ type responseGameWebSocketHandlerError struct {
 type gameWebSocketHandler struct {
 	logger       logrus.FieldLogger
 	groupManager *connections.ConnectionGroupManager
+	sessions     *sessions.Store
 }
 
 type ErrGameWebSocketHandler string
@@ -43,10 +57,12 @@ func (e ErrGameWebSocketHandler) Error() string {
 	return "game web-socket handler error: " + string(e)
 }
 
-func NewGameWebSocketHandler(logger logrus.FieldLogger, groupManager *connections.ConnectionGroupManager) http.Handler {
+func NewGameWebSocketHandler(logger logrus.FieldLogger, groupManager *connections.ConnectionGroupManager,
+	sessionStore *sessions.Store) http.Handler {
 	return &gameWebSocketHandler{
 		logger:       logger,
 		groupManager: groupManager,
+		sessions:     sessionStore,
 	}
 }
 
@@ -87,6 +103,72 @@ func (h *gameWebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// Old clients do not know about structured messages
+	chatFormat := connections.ChatFormatText
+	if value := r.URL.Query().Get(queryFieldChat); value != "" {
+		if chatFormat, err = connections.ParseChatFormat(value); err != nil {
+			h.logger.Warn(ErrGameWebSocketHandler(err.Error()))
+			h.writeResponseJSON(w, http.StatusBadRequest, &responseGameWebSocketHandlerError{
+				Code: http.StatusBadRequest,
+				Text: "invalid chat format",
+			})
+			return
+		}
+	}
+
+	if value := r.URL.Query().Get(queryFieldSpectate); value != "" {
+		spectate, err := strconv.ParseBool(value)
+		if err != nil {
+			h.logger.Warn(ErrGameWebSocketHandler(err.Error()))
+			h.writeResponseJSON(w, http.StatusBadRequest, &responseGameWebSocketHandlerError{
+				Code: http.StatusBadRequest,
+				Text: "invalid spectate",
+			})
+			return
+		}
+		if spectate {
+			h.spectate(w, r, group, chatFormat)
+			return
+		}
+	}
+
+	p, err := parseProfile(r.URL.Query())
+	if err != nil {
+		h.logger.Warn(ErrGameWebSocketHandler(err.Error()))
+		h.writeResponseJSON(w, http.StatusBadRequest, &responseGameWebSocketHandlerError{
+			Code: http.StatusBadRequest,
+			Text: err.Error(),
+		})
+		return
+	}
+
+	session, err := h.session(r.URL.Query(), p)
+	if err != nil {
+		h.logger.Warn(ErrGameWebSocketHandler(err.Error()))
+		if err == sessions.ErrSessionNotFound {
+			h.writeResponseJSON(w, http.StatusUnauthorized, &responseGameWebSocketHandlerError{
+				Code: http.StatusUnauthorized,
+				Text: "unknown session token",
+			})
+		} else {
+			h.writeResponseJSON(w, http.StatusInternalServerError, &responseGameWebSocketHandlerError{
+				Code: http.StatusInternalServerError,
+				Text: "cannot create session",
+			})
+		}
+		return
+	}
+
+	team := r.URL.Query().Get(queryFieldTeam)
+	if team != "" && !containsString(group.GetTeams(), team) {
+		h.logger.Warn(ErrGameWebSocketHandler("unknown team"))
+		h.writeResponseJSON(w, http.StatusBadRequest, &responseGameWebSocketHandlerError{
+			Code: http.StatusBadRequest,
+			Text: "unknown team",
+		})
+		return
+	}
+
 	if group.IsFull() {
 		h.logger.Warn(ErrGameWebSocketHandler("group is full"))
 		h.writeResponseJSON(w, http.StatusServiceUnavailable, &responseGameWebSocketHandlerError{
@@ -112,12 +194,82 @@ func (h *gameWebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 
 	h.logger.Info("start connection worker")
 
-	if err := group.Handle(connections.NewConnectionWorker(conn, h.logger)); err != nil {
+	if err := group.Handle(connections.NewConnectionWorker(conn, h.logger, session, team, chatFormat)); err != nil {
 		h.logger.Error(ErrGameWebSocketHandler(err.Error()))
 		return
 	}
 }
 
+// spectate connects spectator to game group. Spectator does not get snake and does not take place of player
+func (h *gameWebSocketHandler) spectate(w http.ResponseWriter, r *http.Request, group *connections.ConnectionGroup,
+	chatFormat connections.ChatFormat) {
+	if group.IsSpectatorsFull() {
+		h.logger.Warn(ErrGameWebSocketHandler("spectators limit reached"))
+		h.writeResponseJSON(w, http.StatusServiceUnavailable, &responseGameWebSocketHandlerError{
+			Code: http.StatusServiceUnavailable,
+			Text: "spectators limit reached",
+		})
+		return
+	}
+
+	h.logger.Info("upgrade spectator connection")
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		h.logger.Error(ErrGameWebSocketHandler(err.Error()))
+		h.writeResponseJSON(w, http.StatusInternalServerError, &responseGameWebSocketHandlerError{
+			Code: http.StatusInternalServerError,
+			Text: "web-socket upgrade connection error",
+		})
+		return
+	}
+
+	conn.SetReadLimit(wsReadMessageLimit)
+
+	h.logger.Info("start spectator connection worker")
+
+	if err := group.HandleSpectator(connections.NewSpectatorWorker(conn, h.logger, chatFormat)); err != nil {
+		h.logger.Error(ErrGameWebSocketHandler(err.Error()))
+		return
+	}
+}
+
+// session returns session of presented token or issues new session. Profile fields passed with token replace
+// profile of the session
+func (h *gameWebSocketHandler) session(query url.Values, p *profile.Profile) (*sessions.Session, error) {
+	token := query.Get(queryFieldToken)
+	if token == "" {
+		return h.sessions.Create(p)
+	}
+
+	session, err := h.sessions.Get(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if query.Get(queryFieldNickname) != "" || query.Get(queryFieldColor) != "" || query.Get(queryFieldSkin) != "" {
+		if err := h.sessions.UpdateProfile(session, p); err != nil {
+			// Profile is replaced in memory anyway
+			h.logger.Warn(ErrGameWebSocketHandler(err.Error()))
+		}
+	}
+
+	return session, nil
+}
+
+// parseProfile reads player profile from query or form values
+func parseProfile(values url.Values) (*profile.Profile, error) {
+	var skin uint64
+	if skinValue := values.Get(queryFieldSkin); skinValue != "" {
+		var err error
+		if skin, err = strconv.ParseUint(skinValue, 10, 8); err != nil {
+			return nil, profile.ErrInvalidSkin
+		}
+	}
+
+	return profile.NewProfile(values.Get(queryFieldNickname), values.Get(queryFieldColor), uint8(skin))
+}
+
 func (h *gameWebSocketHandler) writeResponseJSON(w http.ResponseWriter, statusCode int, response interface{}) {
 	w.WriteHeader(statusCode)
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
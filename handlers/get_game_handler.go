@@ -16,11 +16,14 @@ const URLRouteGetGameByID = "/games/{id}"
 const MethodGetGame = http.MethodGet
 
 type responseGetGameHandler struct {
-	ID     int `json:"id"`
-	Limit  int `json:"limit"`
-	Count  int `json:"count"`
-	Width  int `json:"width"`
-	Height int `json:"height"`
+	ID             int `json:"id"`
+	Limit          int `json:"limit"`
+	Count          int `json:"count"`
+	SpectatorLimit int `json:"spectator_limit"`
+	SpectatorCount int `json:"spectator_count"`
+	Bots           int `json:"bots"`
+	Width          int `json:"width"`
+	Height         int `json:"height"`
 }
 
 type responseGetGameHandlerError struct {
@@ -85,11 +88,14 @@ func (h *getGameHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	h.writeResponseJSON(w, http.StatusOK, &responseGetGameHandler{
-		ID:     id,
-		Limit:  group.GetLimit(),
-		Count:  group.GetCount(),
-		Width:  int(group.GetWorldWidth()),
-		Height: int(group.GetWorldHeight()),
+		ID:             id,
+		Limit:          group.GetLimit(),
+		Count:          group.GetCount(),
+		SpectatorLimit: group.GetSpectatorLimit(),
+		SpectatorCount: group.GetSpectatorCount(),
+		Bots:           group.GetBotCount(),
+		Width:          int(group.GetWorldWidth()),
+		Height:         int(group.GetWorldHeight()),
 	})
 }
 
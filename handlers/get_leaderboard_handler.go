@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/leaderboard"
+)
+
+const URLRouteGetLeaderboard = "/leaderboard"
+
+const MethodGetLeaderboard = http.MethodGet
+
+const (
+	queryFieldPeriod = "period"
+	queryFieldOffset = "offset"
+	queryFieldLimit  = "limit"
+)
+
+// Page size of leaderboard
+const (
+	defaultLeaderboardLimit = 20
+	maxLeaderboardLimit     = 100
+)
+
+type responseGetLeaderboardHandler struct {
+	Period   leaderboard.Period    `json:"period"`
+	Offset   int                   `json:"offset"`
+	Limit    int                   `json:"limit"`
+	Total    int                   `json:"total"`
+	Rankings []leaderboard.Ranking `json:"rankings"`
+}
+
+type responseGetLeaderboardHandlerError struct {
+	Code int    `json:"code"`
+	Text string `json:"text"`
+}
+
+type getLeaderboardHandler struct {
+	logger      logrus.FieldLogger
+	leaderboard *leaderboard.Leaderboard
+}
+
+type ErrGetLeaderboardHandler string
+
+func (e ErrGetLeaderboardHandler) Error() string {
+	return "get leaderboard handler error: " + string(e)
+}
+
+func NewGetLeaderboardHandler(logger logrus.FieldLogger, board *leaderboard.Leaderboard) http.Handler {
+	return &getLeaderboardHandler{
+		logger:      logger,
+		leaderboard: board,
+	}
+}
+
+func (h *getLeaderboardHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	period, err := leaderboard.ParsePeriod(query.Get(queryFieldPeriod))
+	if err != nil {
+		h.logger.Warn(ErrGetLeaderboardHandler(err.Error()))
+		h.writeResponseJSON(w, http.StatusBadRequest, &responseGetLeaderboardHandlerError{
+			Code: http.StatusBadRequest,
+			Text: "invalid period",
+		})
+		return
+	}
+
+	offset := 0
+	if value := query.Get(queryFieldOffset); value != "" {
+		if offset, err = strconv.Atoi(value); err != nil || offset < 0 {
+			h.logger.Warn(ErrGetLeaderboardHandler("invalid offset"))
+			h.writeResponseJSON(w, http.StatusBadRequest, &responseGetLeaderboardHandlerError{
+				Code: http.StatusBadRequest,
+				Text: "invalid offset",
+			})
+			return
+		}
+	}
+
+	limit := defaultLeaderboardLimit
+	if value := query.Get(queryFieldLimit); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 || limit > maxLeaderboardLimit {
+			h.logger.Warn(ErrGetLeaderboardHandler("invalid limit"))
+			h.writeResponseJSON(w, http.StatusBadRequest, &responseGetLeaderboardHandlerError{
+				Code: http.StatusBadRequest,
+				Text: "invalid limit",
+			})
+			return
+		}
+	}
+
+	rankings, total := h.leaderboard.Rankings(period, time.Now(), offset, limit)
+
+	h.writeResponseJSON(w, http.StatusOK, &responseGetLeaderboardHandler{
+		Period:   period,
+		Offset:   offset,
+		Limit:    limit,
+		Total:    total,
+		Rankings: rankings,
+	})
+}
+
+func (h *getLeaderboardHandler) writeResponseJSON(w http.ResponseWriter, statusCode int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error(ErrGetLeaderboardHandler(err.Error()))
+	}
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/connections"
+	"github.com/ivan1993spb/snake-server/game"
+)
+
+const URLRouteGetScoreboardByID = "/games/{id}/scoreboard"
+
+const MethodGetScoreboard = http.MethodGet
+
+type responseGetScoreboardHandler struct {
+	ID         int                  `json:"id"`
+	Scoreboard []game.ScoreboardRow `json:"scoreboard"`
+}
+
+type responseGetScoreboardHandlerError struct {
+	Code int    `json:"code"`
+	Text string `json:"text"`
+	ID   int    `json:"id"`
+}
+
+type getScoreboardHandler struct {
+	logger       logrus.FieldLogger
+	groupManager *connections.ConnectionGroupManager
+}
+
+type ErrGetScoreboardHandler string
+
+func (e ErrGetScoreboardHandler) Error() string {
+	return "get scoreboard handler error: " + string(e)
+}
+
+func NewGetScoreboardHandler(logger logrus.FieldLogger, groupManager *connections.ConnectionGroupManager) http.Handler {
+	return &getScoreboardHandler{
+		logger:       logger,
+		groupManager: groupManager,
+	}
+}
+
+func (h *getScoreboardHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.logger.Error(ErrGetScoreboardHandler(err.Error()))
+		h.writeResponseJSON(w, http.StatusBadRequest, &responseGetScoreboardHandlerError{
+			Code: http.StatusBadRequest,
+			Text: "invalid game id",
+			ID:   id,
+		})
+		return
+	}
+
+	group, err := h.groupManager.Get(id)
+	if err != nil {
+		h.logger.Error(ErrGetScoreboardHandler(err.Error()))
+
+		switch err {
+		case connections.ErrNotFoundGroup:
+			h.writeResponseJSON(w, http.StatusNotFound, &responseGetScoreboardHandlerError{
+				Code: http.StatusNotFound,
+				Text: "game not found",
+				ID:   id,
+			})
+		default:
+			h.writeResponseJSON(w, http.StatusInternalServerError, &responseGetScoreboardHandlerError{
+				Code: http.StatusInternalServerError,
+				Text: "unknown error",
+				ID:   id,
+			})
+		}
+		return
+	}
+
+	h.writeResponseJSON(w, http.StatusOK, &responseGetScoreboardHandler{
+		ID:         id,
+		Scoreboard: group.GetScoreboard(),
+	})
+}
+
+func (h *getScoreboardHandler) writeResponseJSON(w http.ResponseWriter, statusCode int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error(ErrGetScoreboardHandler(err.Error()))
+	}
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/connections"
+	"github.com/ivan1993spb/snake-server/objects/registry"
+)
+
+const URLRouteGetSnapshotByID = "/games/{id}/snapshot"
+
+const MethodGetSnapshot = http.MethodGet
+
+type responseGetSnapshotHandler struct {
+	ID      int                  `json:"id"`
+	Objects []*registry.Snapshot `json:"objects"`
+}
+
+type responseGetSnapshotHandlerError struct {
+	Code int    `json:"code"`
+	Text string `json:"text"`
+	ID   int    `json:"id"`
+}
+
+type getSnapshotHandler struct {
+	logger       logrus.FieldLogger
+	groupManager *connections.ConnectionGroupManager
+}
+
+type ErrGetSnapshotHandler string
+
+func (e ErrGetSnapshotHandler) Error() string {
+	return "get snapshot handler error: " + string(e)
+}
+
+func NewGetSnapshotHandler(logger logrus.FieldLogger, groupManager *connections.ConnectionGroupManager) http.Handler {
+	return &getSnapshotHandler{
+		logger:       logger,
+		groupManager: groupManager,
+	}
+}
+
+func (h *getSnapshotHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.logger.Error(ErrGetSnapshotHandler(err.Error()))
+		h.writeResponseJSON(w, http.StatusBadRequest, &responseGetSnapshotHandlerError{
+			Code: http.StatusBadRequest,
+			Text: "invalid game id",
+			ID:   id,
+		})
+		return
+	}
+
+	group, err := h.groupManager.Get(id)
+	if err != nil {
+		h.logger.Error(ErrGetSnapshotHandler(err.Error()))
+
+		switch err {
+		case connections.ErrNotFoundGroup:
+			h.writeResponseJSON(w, http.StatusNotFound, &responseGetSnapshotHandlerError{
+				Code: http.StatusNotFound,
+				Text: "game not found",
+				ID:   id,
+			})
+		default:
+			h.writeResponseJSON(w, http.StatusInternalServerError, &responseGetSnapshotHandlerError{
+				Code: http.StatusInternalServerError,
+				Text: "unknown error",
+				ID:   id,
+			})
+		}
+		return
+	}
+
+	snapshot, err := group.GetSnapshot()
+	if err != nil {
+		h.logger.Error(ErrGetSnapshotHandler(err.Error()))
+		h.writeResponseJSON(w, http.StatusInternalServerError, &responseGetSnapshotHandlerError{
+			Code: http.StatusInternalServerError,
+			Text: "cannot take snapshot",
+			ID:   id,
+		})
+		return
+	}
+
+	h.writeResponseJSON(w, http.StatusOK, &responseGetSnapshotHandler{
+		ID:      id,
+		Objects: snapshot,
+	})
+}
+
+func (h *getSnapshotHandler) writeResponseJSON(w http.ResponseWriter, statusCode int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error(ErrGetSnapshotHandler(err.Error()))
+	}
+}
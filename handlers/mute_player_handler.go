@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/connections"
+)
+
+const URLRouteMutePlayerByID = "/games/{id}/mutes"
+
+const MethodMutePlayer = http.MethodPost
+
+const (
+	postFieldMutePlayer   = "player"
+	postFieldMuteDuration = "duration"
+)
+
+type responseMutePlayerHandler struct {
+	ID     int    `json:"id"`
+	Player string `json:"player"`
+	// Duration is empty if player is muted until unmute
+	Duration string `json:"duration,omitempty"`
+}
+
+type responseMutePlayerHandlerError struct {
+	Code int    `json:"code"`
+	Text string `json:"text"`
+	ID   int    `json:"id"`
+}
+
+type mutePlayerHandler struct {
+	logger       logrus.FieldLogger
+	groupManager *connections.ConnectionGroupManager
+}
+
+type ErrMutePlayerHandler string
+
+func (e ErrMutePlayerHandler) Error() string {
+	return "mute player handler error: " + string(e)
+}
+
+func NewMutePlayerHandler(logger logrus.FieldLogger, groupManager *connections.ConnectionGroupManager) http.Handler {
+	return &mutePlayerHandler{
+		logger:       logger,
+		groupManager: groupManager,
+	}
+}
+
+func (h *mutePlayerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.logger.Error(ErrMutePlayerHandler(err.Error()))
+		h.writeResponseJSON(w, http.StatusBadRequest, &responseMutePlayerHandlerError{
+			Code: http.StatusBadRequest,
+			Text: "invalid game id",
+			ID:   id,
+		})
+		return
+	}
+
+	player := strings.TrimSpace(r.PostFormValue(postFieldMutePlayer))
+	if player == "" {
+		h.logger.Warn(ErrMutePlayerHandler("empty player"))
+		h.writeResponseJSON(w, http.StatusBadRequest, &responseMutePlayerHandlerError{
+			Code: http.StatusBadRequest,
+			Text: "invalid player",
+			ID:   id,
+		})
+		return
+	}
+
+	var duration time.Duration
+	if value := r.PostFormValue(postFieldMuteDuration); value != "" {
+		duration, err = time.ParseDuration(value)
+		if err != nil || duration < 0 {
+			h.logger.Warn(ErrMutePlayerHandler("invalid duration"))
+			h.writeResponseJSON(w, http.StatusBadRequest, &responseMutePlayerHandlerError{
+				Code: http.StatusBadRequest,
+				Text: "invalid duration",
+				ID:   id,
+			})
+			return
+		}
+	}
+
+	group, err := h.groupManager.Get(id)
+	if err != nil {
+		h.logger.Error(ErrMutePlayerHandler(err.Error()))
+
+		switch err {
+		case connections.ErrNotFoundGroup:
+			h.writeResponseJSON(w, http.StatusNotFound, &responseMutePlayerHandlerError{
+				Code: http.StatusNotFound,
+				Text: "game not found",
+				ID:   id,
+			})
+		default:
+			h.writeResponseJSON(w, http.StatusInternalServerError, &responseMutePlayerHandlerError{
+				Code: http.StatusInternalServerError,
+				Text: "unknown error",
+				ID:   id,
+			})
+		}
+		return
+	}
+
+	group.MutePlayer(player, duration)
+
+	h.logger.WithFields(logrus.Fields{
+		"group":    id,
+		"player":   player,
+		"duration": duration,
+	}).Info("mute player")
+
+	response := &responseMutePlayerHandler{
+		ID:     id,
+		Player: player,
+	}
+	if duration > 0 {
+		response.Duration = duration.String()
+	}
+
+	h.writeResponseJSON(w, http.StatusCreated, response)
+}
+
+func (h *mutePlayerHandler) writeResponseJSON(w http.ResponseWriter, statusCode int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error(ErrMutePlayerHandler(err.Error()))
+	}
+}
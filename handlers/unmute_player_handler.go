@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/connections"
+)
+
+const URLRouteUnmutePlayerByID = "/games/{id}/mutes/{player}"
+
+const MethodUnmutePlayer = http.MethodDelete
+
+type responseUnmutePlayerHandler struct {
+	ID     int    `json:"id"`
+	Player string `json:"player"`
+}
+
+type responseUnmutePlayerHandlerError struct {
+	Code int    `json:"code"`
+	Text string `json:"text"`
+	ID   int    `json:"id"`
+}
+
+type unmutePlayerHandler struct {
+	logger       logrus.FieldLogger
+	groupManager *connections.ConnectionGroupManager
+}
+
+type ErrUnmutePlayerHandler string
+
+func (e ErrUnmutePlayerHandler) Error() string {
+	return "unmute player handler error: " + string(e)
+}
+
+func NewUnmutePlayerHandler(logger logrus.FieldLogger, groupManager *connections.ConnectionGroupManager) http.Handler {
+	return &unmutePlayerHandler{
+		logger:       logger,
+		groupManager: groupManager,
+	}
+}
+
+func (h *unmutePlayerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.logger.Error(ErrUnmutePlayerHandler(err.Error()))
+		h.writeResponseJSON(w, http.StatusBadRequest, &responseUnmutePlayerHandlerError{
+			Code: http.StatusBadRequest,
+			Text: "invalid game id",
+			ID:   id,
+		})
+		return
+	}
+
+	player := vars["player"]
+
+	group, err := h.groupManager.Get(id)
+	if err != nil {
+		h.logger.Error(ErrUnmutePlayerHandler(err.Error()))
+
+		switch err {
+		case connections.ErrNotFoundGroup:
+			h.writeResponseJSON(w, http.StatusNotFound, &responseUnmutePlayerHandlerError{
+				Code: http.StatusNotFound,
+				Text: "game not found",
+				ID:   id,
+			})
+		default:
+			h.writeResponseJSON(w, http.StatusInternalServerError, &responseUnmutePlayerHandlerError{
+				Code: http.StatusInternalServerError,
+				Text: "unknown error",
+				ID:   id,
+			})
+		}
+		return
+	}
+
+	if !group.UnmutePlayer(player) {
+		h.logger.Warn(ErrUnmutePlayerHandler("player is not muted"))
+		h.writeResponseJSON(w, http.StatusNotFound, &responseUnmutePlayerHandlerError{
+			Code: http.StatusNotFound,
+			Text: "player is not muted",
+			ID:   id,
+		})
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"group":  id,
+		"player": player,
+	}).Info("unmute player")
+
+	h.writeResponseJSON(w, http.StatusOK, &responseUnmutePlayerHandler{
+		ID:     id,
+		Player: player,
+	})
+}
+
+func (h *unmutePlayerHandler) writeResponseJSON(w http.ResponseWriter, statusCode int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error(ErrUnmutePlayerHandler(err.Error()))
+	}
+}
@@ -0,0 +1,267 @@
+// Package leaderboard keeps results of players in append-only log on local disk
+package leaderboard
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Period limits results which are ranked
+type Period string
+
+const (
+	PeriodAll    Period = "all"
+	PeriodDaily  Period = "daily"
+	PeriodWeekly Period = "weekly"
+)
+
+var periodDurations = map[Period]time.Duration{
+	PeriodDaily:  time.Hour * 24,
+	PeriodWeekly: time.Hour * 24 * 7,
+}
+
+// Results older than the longest period are not kept in memory
+const recentDuration = time.Hour * 24 * 7
+
+type ErrLeaderboard string
+
+func (e ErrLeaderboard) Error() string {
+	return "leaderboard error: " + string(e)
+}
+
+const ErrUnknownPeriod = ErrLeaderboard("unknown period")
+
+// ParsePeriod returns period by label. Empty label means all-time period
+func ParsePeriod(label string) (Period, error) {
+	switch Period(label) {
+	case "", PeriodAll:
+		return PeriodAll, nil
+	case PeriodDaily, PeriodWeekly:
+		return Period(label), nil
+	}
+	return "", ErrUnknownPeriod
+}
+
+// Entry is result of one game played by player: one life of a snake
+type Entry struct {
+	Player   string    `json:"player"`
+	Nickname string    `json:"nickname"`
+	Length   uint16    `json:"length"`
+	Kills    uint16    `json:"kills"`
+	Time     time.Time `json:"time"`
+}
+
+// Ranking contains aggregated results of player
+type Ranking struct {
+	Place      int    `json:"place"`
+	Player     string `json:"player"`
+	Nickname   string `json:"nickname"`
+	BestLength uint16 `json:"best_length"`
+	Kills      uint32 `json:"kills"`
+	Games      uint32 `json:"games"`
+}
+
+func (r *Ranking) add(entry Entry) {
+	r.Nickname = entry.Nickname
+	if entry.Length > r.BestLength {
+		r.BestLength = entry.Length
+	}
+	r.Kills += uint32(entry.Kills)
+	r.Games++
+}
+
+type Leaderboard struct {
+	// file is nil if leaderboard is not persisted
+	file *os.File
+
+	all    map[string]*Ranking
+	recent []Entry
+
+	mux *sync.RWMutex
+}
+
+// Open loads leaderboard from log file at passed path and appends new results to the file. Empty path means that
+// leaderboard is kept in memory only
+func Open(path string) (*Leaderboard, error) {
+	l := &Leaderboard{
+		all:    map[string]*Ranking{},
+		recent: []Entry{},
+		mux:    &sync.RWMutex{},
+	}
+
+	if path == "" {
+		return l, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, ErrLeaderboard("cannot open log: " + err.Error())
+	}
+
+	if err := l.load(file); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	l.file = file
+
+	return l, nil
+}
+
+func (l *Leaderboard) load(file *os.File) error {
+	now := time.Now()
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// Skip broken line, for example if server was stopped during writing
+			continue
+		}
+
+		l.unsafeAdd(entry, now)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return ErrLeaderboard("cannot read log: " + err.Error())
+	}
+
+	return nil
+}
+
+// Record saves result of player
+func (l *Leaderboard) Record(entry Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	if l.file != nil {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return ErrLeaderboard("cannot encode entry: " + err.Error())
+		}
+		if _, err := l.file.Write(append(data, '\n')); err != nil {
+			return ErrLeaderboard("cannot write log: " + err.Error())
+		}
+	}
+
+	l.unsafeAdd(entry, time.Now())
+
+	return nil
+}
+
+func (l *Leaderboard) unsafeAdd(entry Entry, now time.Time) {
+	ranking, ok := l.all[entry.Player]
+	if !ok {
+		ranking = &Ranking{
+			Player: entry.Player,
+		}
+		l.all[entry.Player] = ranking
+	}
+	ranking.add(entry)
+
+	if now.Sub(entry.Time) < recentDuration {
+		l.recent = append(l.recent, entry)
+	}
+
+	// Drop outdated results
+	i := 0
+	for i < len(l.recent) && now.Sub(l.recent[i].Time) >= recentDuration {
+		i++
+	}
+	if i > 0 {
+		l.recent = append(l.recent[:0], l.recent[i:]...)
+	}
+}
+
+// Rankings returns count of ranked players and a page of rankings of passed period
+func (l *Leaderboard) Rankings(period Period, now time.Time, offset, limit int) ([]Ranking, int) {
+	l.mux.RLock()
+	rankings := l.unsafeRankings(period, now)
+	l.mux.RUnlock()
+
+	sort.Slice(rankings, func(i, j int) bool {
+		if rankings[i].BestLength != rankings[j].BestLength {
+			return rankings[i].BestLength > rankings[j].BestLength
+		}
+		if rankings[i].Kills != rankings[j].Kills {
+			return rankings[i].Kills > rankings[j].Kills
+		}
+		if rankings[i].Games != rankings[j].Games {
+			return rankings[i].Games < rankings[j].Games
+		}
+		return rankings[i].Player < rankings[j].Player
+	})
+
+	for i := range rankings {
+		rankings[i].Place = i + 1
+	}
+
+	total := len(rankings)
+
+	if offset >= total {
+		return []Ranking{}, total
+	}
+	if offset+limit > total {
+		limit = total - offset
+	}
+
+	return rankings[offset : offset+limit], total
+}
+
+func (l *Leaderboard) unsafeRankings(period Period, now time.Time) []Ranking {
+	duration, ok := periodDurations[period]
+	if !ok {
+		rankings := make([]Ranking, 0, len(l.all))
+		for _, ranking := range l.all {
+			rankings = append(rankings, *ranking)
+		}
+		return rankings
+	}
+
+	players := map[string]*Ranking{}
+	for _, entry := range l.recent {
+		if now.Sub(entry.Time) >= duration {
+			continue
+		}
+		ranking, ok := players[entry.Player]
+		if !ok {
+			ranking = &Ranking{
+				Player: entry.Player,
+			}
+			players[entry.Player] = ranking
+		}
+		ranking.add(entry)
+	}
+
+	rankings := make([]Ranking, 0, len(players))
+	for _, ranking := range players {
+		rankings = append(rankings, *ranking)
+	}
+	return rankings
+}
+
+// Close closes log file
+func (l *Leaderboard) Close() error {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
@@ -0,0 +1,77 @@
+package leaderboard
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Leaderboard_Record_PersistsResults(t *testing.T) {
+	dir, err := ioutil.TempDir("", "leaderboard")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "leaderboard.log")
+
+	l, err := Open(path)
+	require.Nil(t, err)
+
+	now := time.Now()
+	require.Nil(t, l.Record(Entry{Player: "first", Nickname: "Ivan", Length: 10, Kills: 1, Time: now}))
+	require.Nil(t, l.Record(Entry{Player: "first", Nickname: "Ivan", Length: 25, Kills: 2, Time: now}))
+	require.Nil(t, l.Record(Entry{Player: "second", Nickname: "Anna", Length: 15, Time: now}))
+	require.Nil(t, l.Close())
+
+	l, err = Open(path)
+	require.Nil(t, err)
+	defer l.Close()
+
+	rankings, total := l.Rankings(PeriodAll, now, 0, 10)
+	require.Equal(t, 2, total)
+	require.Equal(t, []Ranking{
+		{Place: 1, Player: "first", Nickname: "Ivan", BestLength: 25, Kills: 3, Games: 2},
+		{Place: 2, Player: "second", Nickname: "Anna", BestLength: 15, Kills: 0, Games: 1},
+	}, rankings)
+}
+
+func Test_Leaderboard_Rankings_FiltersPeriodAndPaginates(t *testing.T) {
+	l, err := Open("")
+	require.Nil(t, err)
+
+	now := time.Now()
+	require.Nil(t, l.Record(Entry{Player: "old", Length: 50, Time: now.Add(-time.Hour * 24 * 3)}))
+	require.Nil(t, l.Record(Entry{Player: "first", Length: 30, Time: now.Add(-time.Hour)}))
+	require.Nil(t, l.Record(Entry{Player: "second", Length: 20, Time: now.Add(-time.Minute)}))
+
+	rankings, total := l.Rankings(PeriodDaily, now, 0, 10)
+	require.Equal(t, 2, total)
+	require.Equal(t, "first", rankings[0].Player)
+	require.Equal(t, "second", rankings[1].Player)
+
+	rankings, total = l.Rankings(PeriodWeekly, now, 1, 1)
+	require.Equal(t, 3, total)
+	require.Len(t, rankings, 1)
+	require.Equal(t, "first", rankings[0].Player)
+	require.Equal(t, 2, rankings[0].Place)
+
+	rankings, total = l.Rankings(PeriodAll, now, 5, 10)
+	require.Equal(t, 3, total)
+	require.Empty(t, rankings)
+}
+
+func Test_ParsePeriod(t *testing.T) {
+	period, err := ParsePeriod("")
+	require.Nil(t, err)
+	require.Equal(t, PeriodAll, period)
+
+	period, err = ParsePeriod("weekly")
+	require.Nil(t, err)
+	require.Equal(t, PeriodWeekly, period)
+
+	_, err = ParsePeriod("monthly")
+	require.Equal(t, ErrUnknownPeriod, err)
+}
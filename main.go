@@ -14,7 +14,9 @@ import (
 
 	"github.com/ivan1993spb/snake-server/connections"
 	"github.com/ivan1993spb/snake-server/handlers"
+	"github.com/ivan1993spb/snake-server/leaderboard"
 	"github.com/ivan1993spb/snake-server/middlewares"
+	"github.com/ivan1993spb/snake-server/sessions"
 )
 
 const ServerName = "Snake-Server"
@@ -43,6 +45,10 @@ var (
 
 	flagJSONLog bool
 	logLevel    string
+
+	leaderboardPath string
+	sessionsPath    string
+	adminToken      string
 )
 
 func usage() {
@@ -62,6 +68,9 @@ func init() {
 	flag.Int64Var(&seed, "seed", time.Now().UnixNano(), "random seed")
 	flag.BoolVar(&flagJSONLog, "log-json", false, "use json format for logger")
 	flag.StringVar(&logLevel, "log-level", "info", "set log level: panic, fatal, error, warning (warn), info or debug")
+	flag.StringVar(&leaderboardPath, "leaderboard", "", "path to leaderboard log file, leaderboard is not persisted if path is empty")
+	flag.StringVar(&sessionsPath, "sessions", "", "path to sessions log file, sessions are not persisted if path is empty")
+	flag.StringVar(&adminToken, "admin-token", "", "token of admin requests, admin requests are disabled if token is empty")
 	flag.Usage = usage
 	flag.Parse()
 }
@@ -102,6 +111,7 @@ func main() {
 		"groups_limit": groupsLimit,
 		"seed":         seed,
 		"log_level":    logLevel,
+		"admin":        adminToken != "",
 	}).Info("preparing to start server")
 
 	rand.Seed(seed)
@@ -111,18 +121,38 @@ func main() {
 		logger.Fatalln("cannot create connections group manager:", err)
 	}
 
+	sessionStore, err := sessions.Open(sessionsPath)
+	if err != nil {
+		logger.Fatalln("cannot open sessions:", err)
+	}
+	defer sessionStore.Close()
+
+	board, err := leaderboard.Open(leaderboardPath)
+	if err != nil {
+		logger.Fatalln("cannot open leaderboard:", err)
+	}
+	defer board.Close()
+
 	rootRouter := mux.NewRouter()
 
 	// Web-Socket route
-	rootRouter.Path(handlers.URLRouteGameWebSocketByID).Methods(handlers.MethodGame).Handler(handlers.NewGameWebSocketHandler(logger, groupManager))
+	rootRouter.Path(handlers.URLRouteGameWebSocketByID).Methods(handlers.MethodGame).Handler(handlers.NewGameWebSocketHandler(logger, groupManager, sessionStore))
 
 	// API routes
 	apiRouter := mux.NewRouter().StrictSlash(true)
 	apiRouter.Path(handlers.URLRouteGetInfo).Methods(handlers.MethodGetInfo).Handler(handlers.NewGetInfoHandler(logger, Version, Build))
 	apiRouter.Path(handlers.URLRouteGetCapacity).Methods(handlers.MethodGetCapacity).Handler(handlers.NewGetCapacityHandler(logger, groupManager))
-	apiRouter.Path(handlers.URLRouteCreateGame).Methods(handlers.MethodCreateGame).Handler(handlers.NewCreateGameHandler(logger, groupManager))
+	apiRouter.Path(handlers.URLRouteCreateGame).Methods(handlers.MethodCreateGame).Handler(handlers.NewCreateGameHandler(logger, groupManager, board))
 	apiRouter.Path(handlers.URLRouteGetGameByID).Methods(handlers.MethodGetGame).Handler(handlers.NewGetGameHandler(logger, groupManager))
 	apiRouter.Path(handlers.URLRouteDeleteGameByID).Methods(handlers.MethodDeleteGame).Handler(handlers.NewDeleteGameHandler(logger, groupManager))
+	// Admin routes
+	adminAuth := middlewares.NewAdminAuth(logger, adminToken)
+	apiRouter.Path(handlers.URLRouteMutePlayerByID).Methods(handlers.MethodMutePlayer).Handler(negroni.New(adminAuth, negroni.Wrap(handlers.NewMutePlayerHandler(logger, groupManager))))
+	apiRouter.Path(handlers.URLRouteUnmutePlayerByID).Methods(handlers.MethodUnmutePlayer).Handler(negroni.New(adminAuth, negroni.Wrap(handlers.NewUnmutePlayerHandler(logger, groupManager))))
+	apiRouter.Path(handlers.URLRouteGetScoreboardByID).Methods(handlers.MethodGetScoreboard).Handler(handlers.NewGetScoreboardHandler(logger, groupManager))
+	apiRouter.Path(handlers.URLRouteGetSnapshotByID).Methods(handlers.MethodGetSnapshot).Handler(handlers.NewGetSnapshotHandler(logger, groupManager))
+	apiRouter.Path(handlers.URLRouteGetLeaderboard).Methods(handlers.MethodGetLeaderboard).Handler(handlers.NewGetLeaderboardHandler(logger, board))
+	apiRouter.Path(handlers.URLRouteCreateSession).Methods(handlers.MethodCreateSession).Handler(handlers.NewCreateSessionHandler(logger, sessionStore))
 	apiRouter.Path(handlers.URLRouteGetGames).Methods(handlers.MethodGetGames).Handler(handlers.NewGetGamesHandler(logger, groupManager))
 	// Use middlewares for API routes
 	rootRouter.NewRoute().Handler(negroni.New(
@@ -0,0 +1,51 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/negroni"
+)
+
+const authorizationSchemeBearer = "Bearer "
+
+type responseAdminAuthError struct {
+	Code int    `json:"code"`
+	Text string `json:"text"`
+}
+
+// NewAdminAuth lets through requests with header "Authorization: Bearer <token>". If token is empty admin requests
+// are disabled
+func NewAdminAuth(logger logrus.FieldLogger, token string) negroni.Handler {
+	return negroni.HandlerFunc(func(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		if token == "" {
+			writeAdminAuthError(logger, rw, http.StatusForbidden, "admin api disabled")
+			return
+		}
+
+		header := r.Header.Get("Authorization")
+		if !strings.HasPrefix(header, authorizationSchemeBearer) ||
+			subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(header, authorizationSchemeBearer)), []byte(token)) != 1 {
+			logger.WithField("path", r.URL.Path).Warn("unauthorized admin request")
+			writeAdminAuthError(logger, rw, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		next(rw, r)
+	})
+}
+
+func writeAdminAuthError(logger logrus.FieldLogger, rw http.ResponseWriter, statusCode int, text string) {
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	rw.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(rw).Encode(&responseAdminAuthError{
+		Code: statusCode,
+		Text: text,
+	}); err != nil {
+		logger.WithError(err).Error("cannot send response on admin auth error")
+	}
+}
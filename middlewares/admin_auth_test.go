@@ -0,0 +1,36 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/negroni"
+)
+
+func Test_NewAdminAuth_ChecksToken(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	ok := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusCreated)
+	})
+
+	serve := func(token, header string) *httptest.ResponseRecorder {
+		n := negroni.New(NewAdminAuth(logger, token), negroni.Wrap(ok))
+		request := httptest.NewRequest(http.MethodPost, "/games/0/mutes", nil)
+		if header != "" {
+			request.Header.Set("Authorization", header)
+		}
+		recorder := httptest.NewRecorder()
+		n.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	require.Equal(t, http.StatusForbidden, serve("", "Bearer ").Code, "admin requests are disabled without token")
+	require.Equal(t, http.StatusUnauthorized, serve("secret", "").Code)
+	require.Equal(t, http.StatusUnauthorized, serve("secret", "Bearer wrong").Code)
+	require.Equal(t, http.StatusUnauthorized, serve("secret", "secret").Code)
+	require.Equal(t, http.StatusCreated, serve("secret", "Bearer secret").Code)
+}
@@ -49,6 +49,22 @@ func NewApple(world *world.World) (*Apple, error) {
 	return apple, nil
 }
 
+// NewAppleDot creates apple on passed dot
+func NewAppleDot(world *world.World, dot engine.Dot) (*Apple, error) {
+	apple := &Apple{
+		uuid:  uuid.Must(uuid.NewV4()).String(),
+		world: world,
+		dot:   dot,
+		mux:   &sync.RWMutex{},
+	}
+
+	if err := world.CreateObject(apple, engine.Location{dot}); err != nil {
+		return nil, ErrCreateApple(err.Error())
+	}
+
+	return apple, nil
+}
+
 func (a *Apple) String() string {
 	a.mux.RLock()
 	defer a.mux.RUnlock()
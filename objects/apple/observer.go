@@ -0,0 +1,202 @@
+package apple
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects/corpse"
+	"github.com/ivan1993spb/snake-server/objects/registry"
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+const chanAppleObserverEventsBuffer = 32
+
+const chanAppleObserverRefillBuffer = 8
+
+const defaultAppleCount = 1
+
+const oneAppleArea = 50
+
+// Number of tries to find free dot near a center
+const findNearRetries = 16
+
+type Observer struct {
+	config SpawnConfig
+}
+
+func NewObserver(options registry.Options) (registry.Observer, error) {
+	config, err := ParseSpawnConfig(options)
+	if err != nil {
+		return nil, err
+	}
+	return Observer{
+		config: config,
+	}, nil
+}
+
+func (o Observer) Observe(stop <-chan struct{}, w *world.World, logger logrus.FieldLogger) {
+	go func() {
+		logger.WithFields(logrus.Fields{
+			"strategy": o.config.Strategy,
+			"target":   o.config.Target(w.Size(), 0),
+			"cap":      o.config.Cap,
+		}).Debug("apple spawn config")
+
+		chRefill := make(chan struct{}, chanAppleObserverRefillBuffer)
+
+		var chWave <-chan time.Time
+		if o.config.Strategy == StrategyWaves {
+			ticker := time.NewTicker(o.config.WaveInterval)
+			defer ticker.Stop()
+			chWave = ticker.C
+		}
+
+		chEvents := w.Events(stop, chanAppleObserverEventsBuffer)
+
+		o.refill(w, logger)
+
+		for {
+			select {
+			case event, ok := <-chEvents:
+				if !ok {
+					return
+				}
+				switch event.Type {
+				case world.EventTypeObjectDelete:
+					if _, ok := event.Payload.(*Apple); ok && o.config.Strategy != StrategyWaves {
+						o.scheduleRefill(stop, chRefill)
+					}
+				case world.EventTypeObjectCreate:
+					if _, ok := event.Payload.(*snake.Snake); ok && o.config.Strategy == StrategyPlayers {
+						o.refill(w, logger)
+					}
+				}
+			case <-chRefill:
+				o.refill(w, logger)
+			case <-chWave:
+				o.refill(w, logger)
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+func (o Observer) scheduleRefill(stop <-chan struct{}, chRefill chan<- struct{}) {
+	go func() {
+		if o.config.RespawnDelay > 0 {
+			timer := time.NewTimer(o.config.RespawnDelay)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-stop:
+				return
+			}
+		}
+		select {
+		case chRefill <- struct{}{}:
+		case <-stop:
+		}
+	}()
+}
+
+// refill creates missing apples
+func (o Observer) refill(w *world.World, logger logrus.FieldLogger) {
+	var (
+		apples  int
+		snakes  int
+		corpses []*corpse.Corpse
+	)
+
+	for _, object := range w.GetObjects() {
+		switch object := object.(type) {
+		case *Apple:
+			apples++
+		case *snake.Snake:
+			snakes++
+		case *corpse.Corpse:
+			corpses = append(corpses, object)
+		}
+	}
+
+	missing := o.config.Target(w.Size(), snakes) - apples
+
+	for missing > 0 {
+		var created int
+
+		switch o.config.Strategy {
+		case StrategyCluster:
+			created = o.createCluster(w, missing)
+		case StrategyCorpses:
+			created = o.createNearCorpses(w, corpses)
+		default:
+			if _, err := NewApple(w); err == nil {
+				created = 1
+			}
+		}
+
+		if created == 0 {
+			logger.Error("cannot create apple")
+			return
+		}
+
+		missing -= created
+	}
+}
+
+func (o Observer) createCluster(w *world.World, missing int) int {
+	center := randomDot(w)
+	created := 0
+
+	for i := 0; i < o.config.ClusterSize && created < missing; i++ {
+		if createNear(w, center, clusterRadius) {
+			created++
+		}
+	}
+
+	return created
+}
+
+func (o Observer) createNearCorpses(w *world.World, corpses []*corpse.Corpse) int {
+	if len(corpses) > 0 {
+		location := corpses[rand.Intn(len(corpses))].GetLocation()
+		if !location.Empty() && createNear(w, location.Dot(uint16(rand.Intn(len(location)))), 1) {
+			return 1
+		}
+	}
+
+	if _, err := NewApple(w); err == nil {
+		return 1
+	}
+
+	return 0
+}
+
+// createNear creates apple on a free dot within radius from center
+func createNear(w *world.World, center engine.Dot, radius uint8) bool {
+	for i := 0; i < findNearRetries; i++ {
+		dot, err := w.Shift(center, randomShift(radius), randomShift(radius))
+		if err != nil || w.DotOccupied(dot) {
+			continue
+		}
+		if _, err := NewAppleDot(w, dot); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func randomShift(radius uint8) int {
+	return rand.Intn(int(radius)*2+1) - int(radius)
+}
+
+func randomDot(w *world.World) engine.Dot {
+	return engine.Dot{
+		X: uint8(rand.Intn(int(w.Width()))),
+		Y: uint8(rand.Intn(int(w.Height()))),
+	}
+}
@@ -0,0 +1,33 @@
+package apple
+
+import (
+	"github.com/pquerna/ffjson/ffjson"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects/registry"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+func init() {
+	registry.Register(registry.Type{
+		Label:  appleTypeLabel,
+		Object: &Apple{},
+		Snapshot: func(object interface{}) ([]byte, error) {
+			a := object.(*Apple)
+			a.mux.RLock()
+			defer a.mux.RUnlock()
+			return ffjson.Marshal(a.dot)
+		},
+		Restore:     restoreApple,
+		Collide:     registry.CollideFood,
+		NewObserver: NewObserver,
+	})
+}
+
+func restoreApple(stop <-chan struct{}, world *world.World, data []byte) (interface{}, error) {
+	var dot engine.Dot
+	if err := ffjson.Unmarshal(data, &dot); err != nil {
+		return nil, ErrCreateApple(err.Error())
+	}
+	return NewAppleDot(world, dot)
+}
@@ -0,0 +1,159 @@
+package apple
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ivan1993spb/snake-server/objects/registry"
+)
+
+// Spawn strategies define how many apples are on playground and where new apples appear
+const (
+	// StrategyFixed keeps fixed count of apples
+	StrategyFixed = "fixed"
+	// StrategyDensity keeps one apple per density dots of playground
+	StrategyDensity = "density"
+	// StrategyPlayers keeps count apples per snake on playground
+	StrategyPlayers = "players"
+	// StrategyCluster keeps apples as density strategy but creates them in clusters
+	StrategyCluster = "cluster"
+	// StrategyCorpses keeps apples as density strategy and creates them near corpses
+	StrategyCorpses = "corpses"
+	// StrategyWaves creates apples as density strategy but only once per wave interval
+	StrategyWaves = "waves"
+)
+
+// Options of apple object type
+const (
+	optionStrategy     = "strategy"
+	optionCount        = "count"
+	optionDensity      = "density"
+	optionCap          = "cap"
+	optionRespawnDelay = "respawn_delay"
+	optionClusterSize  = "cluster_size"
+	optionWaveInterval = "wave_interval"
+)
+
+const (
+	defaultSpawnStrategy = StrategyDensity
+	defaultSpawnCount    = 1
+	defaultClusterSize   = 5
+	defaultWaveInterval  = time.Second * 30
+
+	clusterRadius = 2
+)
+
+// SpawnConfig defines apple economy of a game
+type SpawnConfig struct {
+	Strategy string
+	// Count of apples for fixed strategy or count of apples per snake for players strategy
+	Count int
+	// Density is number of playground dots per apple
+	Density int
+	// Cap limits count of apples. Zero cap means no limit
+	Cap int
+	// RespawnDelay is time after which eaten apple is replaced
+	RespawnDelay time.Duration
+	// ClusterSize is count of apples in one cluster
+	ClusterSize int
+	// WaveInterval is time between waves
+	WaveInterval time.Duration
+}
+
+func DefaultSpawnConfig() SpawnConfig {
+	return SpawnConfig{
+		Strategy:     defaultSpawnStrategy,
+		Count:        defaultSpawnCount,
+		Density:      oneAppleArea,
+		ClusterSize:  defaultClusterSize,
+		WaveInterval: defaultWaveInterval,
+	}
+}
+
+type ErrInvalidOption struct {
+	Option string
+	Value  string
+}
+
+func (e *ErrInvalidOption) Error() string {
+	return fmt.Sprintf("invalid apple option %s: %q", e.Option, e.Value)
+}
+
+// ParseSpawnConfig parses apple object type options
+func ParseSpawnConfig(options registry.Options) (SpawnConfig, error) {
+	config := DefaultSpawnConfig()
+
+	for option, value := range options {
+		var err error
+
+		switch option {
+		case optionStrategy:
+			switch value {
+			case StrategyFixed, StrategyDensity, StrategyPlayers, StrategyCluster, StrategyCorpses, StrategyWaves:
+				config.Strategy = value
+			default:
+				err = &ErrInvalidOption{option, value}
+			}
+		case optionCount:
+			config.Count, err = parsePositiveInt(option, value)
+		case optionDensity:
+			config.Density, err = parsePositiveInt(option, value)
+		case optionCap:
+			config.Cap, err = strconv.Atoi(value)
+			if err != nil || config.Cap < 0 {
+				err = &ErrInvalidOption{option, value}
+			}
+		case optionRespawnDelay:
+			config.RespawnDelay, err = time.ParseDuration(value)
+			if err != nil || config.RespawnDelay < 0 {
+				err = &ErrInvalidOption{option, value}
+			}
+		case optionClusterSize:
+			config.ClusterSize, err = parsePositiveInt(option, value)
+		case optionWaveInterval:
+			config.WaveInterval, err = time.ParseDuration(value)
+			if err != nil || config.WaveInterval <= 0 {
+				err = &ErrInvalidOption{option, value}
+			}
+		default:
+			err = &ErrInvalidOption{option, value}
+		}
+
+		if err != nil {
+			return SpawnConfig{}, err
+		}
+	}
+
+	return config, nil
+}
+
+func parsePositiveInt(option, value string) (int, error) {
+	number, err := strconv.Atoi(value)
+	if err != nil || number <= 0 {
+		return 0, &ErrInvalidOption{option, value}
+	}
+	return number, nil
+}
+
+// Target returns count of apples which must be on playground of passed size with passed count of snakes
+func (c SpawnConfig) Target(size uint16, snakes int) int {
+	target := defaultAppleCount
+
+	switch c.Strategy {
+	case StrategyFixed:
+		target = c.Count
+	case StrategyPlayers:
+		target = c.Count * snakes
+	default:
+		if int(size) > c.Density {
+			target = int(size) / c.Density
+		}
+	}
+
+	if c.Cap > 0 && target > c.Cap {
+		return c.Cap
+	}
+
+	return target
+}
@@ -0,0 +1,66 @@
+package apple
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/objects/registry"
+)
+
+func Test_ParseSpawnConfig_ReturnsDefaultConfig(t *testing.T) {
+	config, err := ParseSpawnConfig(nil)
+	require.Nil(t, err)
+	require.Equal(t, DefaultSpawnConfig(), config)
+}
+
+func Test_ParseSpawnConfig_ParsesOptions(t *testing.T) {
+	config, err := ParseSpawnConfig(registry.Options{
+		"strategy":      "waves",
+		"density":       "20",
+		"cap":           "15",
+		"respawn_delay": "1500ms",
+		"wave_interval": "10s",
+	})
+	require.Nil(t, err)
+	require.Equal(t, StrategyWaves, config.Strategy)
+	require.Equal(t, 20, config.Density)
+	require.Equal(t, 15, config.Cap)
+	require.Equal(t, time.Millisecond*1500, config.RespawnDelay)
+	require.Equal(t, time.Second*10, config.WaveInterval)
+}
+
+func Test_ParseSpawnConfig_ReturnsErrors(t *testing.T) {
+	for _, options := range []registry.Options{
+		{"strategy": "unknown"},
+		{"count": "0"},
+		{"density": "-1"},
+		{"cap": "many"},
+		{"respawn_delay": "-1s"},
+		{"wave_interval": "0s"},
+		{"unknown": "1"},
+	} {
+		_, err := ParseSpawnConfig(options)
+		require.NotNil(t, err, "options: %v", options)
+	}
+}
+
+func Test_SpawnConfig_Target(t *testing.T) {
+	config := DefaultSpawnConfig()
+	require.Equal(t, 200, config.Target(10000, 3))
+	require.Equal(t, 1, config.Target(10, 3))
+
+	config.Cap = 50
+	require.Equal(t, 50, config.Target(10000, 3))
+
+	config = DefaultSpawnConfig()
+	config.Strategy = StrategyFixed
+	config.Count = 7
+	require.Equal(t, 7, config.Target(10000, 3))
+
+	config.Strategy = StrategyPlayers
+	config.Count = 2
+	require.Equal(t, 6, config.Target(10000, 3))
+	require.Equal(t, 0, config.Target(10000, 0))
+}
@@ -0,0 +1,220 @@
+package bomb
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pquerna/ffjson/ffjson"
+	"github.com/satori/go.uuid"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+const bombTypeLabel = "bomb"
+
+// Time after which dropped bomb explodes
+const bombFuse = time.Second * 3
+
+// Area of explosion. Bomb is in the center of the mask
+var explosionMask = engine.DotsMaskExplosion
+
+// Bomb lies on playground until a snake picks it up. Dropped bomb is armed and explodes after fuse timer
+type Bomb struct {
+	uuid     string
+	world    *world.World
+	dot      engine.Dot
+	armed    bool
+	taken    bool
+	exploded bool
+	// owner is object which placed armed bomb. Objects killed by explosion are killed by owner
+	owner interface{}
+	mux   *sync.RWMutex
+}
+
+type ErrCreateBomb string
+
+func (e ErrCreateBomb) Error() string {
+	return "cannot create bomb: " + string(e)
+}
+
+// NewBomb creates and locates new unarmed bomb on random dot
+func NewBomb(world *world.World) (*Bomb, error) {
+	bomb := &Bomb{
+		uuid: uuid.Must(uuid.NewV4()).String(),
+		mux:  &sync.RWMutex{},
+	}
+
+	location, err := world.CreateObjectRandomDot(bomb)
+	if err != nil {
+		return nil, ErrCreateBomb(err.Error())
+	}
+	if len(location) == 0 {
+		return nil, ErrCreateBomb("created empty location")
+	}
+
+	bomb.mux.Lock()
+	bomb.dot = location.Dot(0)
+	bomb.world = world
+	bomb.mux.Unlock()
+
+	return bomb, nil
+}
+
+// NewArmedBomb creates bomb placed by owner on passed dot and runs fuse timer. Owner may be nil
+func NewArmedBomb(stop <-chan struct{}, world *world.World, dot engine.Dot, owner interface{}) (*Bomb, error) {
+	bomb := &Bomb{
+		uuid:  uuid.Must(uuid.NewV4()).String(),
+		world: world,
+		dot:   dot,
+		armed: true,
+		owner: owner,
+		mux:   &sync.RWMutex{},
+	}
+
+	if err := world.CreateObject(bomb, engine.Location{dot}); err != nil {
+		return nil, ErrCreateBomb(err.Error())
+	}
+
+	bomb.fuse(stop)
+
+	return bomb, nil
+}
+
+func (b *Bomb) fuse(stop <-chan struct{}) {
+	go func() {
+		var timer = time.NewTimer(bombFuse)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			b.Detonate()
+		case <-stop:
+		}
+	}()
+}
+
+func (b *Bomb) String() string {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	return fmt.Sprintf("bomb %s", b.dot)
+}
+
+func (b *Bomb) Armed() bool {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	return b.armed
+}
+
+// Owner returns object which placed armed bomb. Owner is nil if bomb was not placed by anybody
+func (b *Bomb) Owner() interface{} {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	return b.owner
+}
+
+// take removes unarmed bomb from playground if carrier accepts it
+func (b *Bomb) take(carrier objects.Carrier) bool {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	if b.armed || b.taken || b.exploded {
+		return false
+	}
+
+	if !carrier.PickUp(b) {
+		return false
+	}
+
+	b.taken = true
+	b.world.DeleteObject(b, engine.Location{b.dot})
+
+	return true
+}
+
+// Break detonates bomb. It is called when bomb is hit by another explosion
+func (b *Bomb) Break(dot engine.Dot) {
+	b.Detonate()
+}
+
+// Detonate removes bomb and destroys objects in explosion area
+func (b *Bomb) Detonate() {
+	b.mux.Lock()
+	if b.exploded || b.taken {
+		b.mux.Unlock()
+		return
+	}
+	b.exploded = true
+	center := b.dot
+	owner := b.owner
+	b.world.DeleteObject(b, engine.Location{center})
+	b.mux.Unlock()
+
+	explosion := &Explosion{
+		Center: center,
+		Dots:   explosionDots(b.world, center),
+	}
+
+	b.world.Explode(explosion)
+
+	for _, dot := range explosion.Dots {
+		destroy(b.world.GetObjectByDot(dot), dot, owner)
+	}
+}
+
+// destroy breaks hard objects, kills alive objects by owner of bomb and removes food
+func destroy(object interface{}, dot engine.Dot, owner interface{}) {
+	switch object := object.(type) {
+	case objects.Hard:
+		object.Break(dot)
+	case objects.Prey:
+		object.KilledBy(dot, owner)
+	case objects.Alive:
+		object.Kill(dot)
+	case objects.Food:
+		object.NutritionalValue(dot)
+	}
+}
+
+// explosionDots returns dots of explosion mask centered on passed dot
+func explosionDots(w *world.World, center engine.Dot) []engine.Dot {
+	offsetX := int(explosionMask.Width() / 2)
+	offsetY := int(explosionMask.Height() / 2)
+
+	mask := explosionMask.Location(0, 0)
+	dots := make([]engine.Dot, 0, len(mask))
+
+	for _, maskDot := range mask {
+		dot, err := w.Shift(center, int(maskDot.X)-offsetX, int(maskDot.Y)-offsetY)
+		if err == nil {
+			dots = append(dots, dot)
+		}
+	}
+
+	return dots
+}
+
+func (b *Bomb) MarshalJSON() ([]byte, error) {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	return ffjson.Marshal(&bomb{
+		UUID:  b.uuid,
+		Dot:   b.dot,
+		Type:  bombTypeLabel,
+		Armed: b.armed,
+	})
+}
+
+type bomb struct {
+	UUID  string     `json:"uuid"`
+	Dot   engine.Dot `json:"dot"`
+	Type  string     `json:"type"`
+	Armed bool       `json:"armed"`
+}
+
+// Explosion is payload of explosion event
+type Explosion struct {
+	Center engine.Dot   `json:"center"`
+	Dots   []engine.Dot `json:"dots"`
+}
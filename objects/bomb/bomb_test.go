@@ -0,0 +1,140 @@
+package bomb
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects/corpse"
+	"github.com/ivan1993spb/snake-server/objects/registry"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+type testCarrier struct {
+	items []interface{}
+	limit int
+}
+
+func (c *testCarrier) PickUp(item interface{}) bool {
+	if len(c.items) >= c.limit {
+		return false
+	}
+	c.items = append(c.items, item)
+	return true
+}
+
+type testPrey struct {
+	killer interface{}
+}
+
+func (p *testPrey) KilledBy(dot engine.Dot, killer interface{}) {
+	p.killer = killer
+}
+
+func newTestBomb(t *testing.T, w *world.World, dot engine.Dot) *Bomb {
+	bomb := &Bomb{
+		uuid:  "test",
+		world: w,
+		dot:   dot,
+		mux:   &sync.RWMutex{},
+	}
+	require.Nil(t, w.CreateObject(bomb, engine.Location{dot}))
+	return bomb
+}
+
+func Test_explosionDots_WrapsAroundWorld(t *testing.T) {
+	w, err := world.NewWorld(20, 20)
+	require.Nil(t, err)
+
+	dots := explosionDots(w, engine.Dot{X: 0, Y: 0})
+
+	require.Len(t, dots, 13)
+	require.Contains(t, dots, engine.Dot{X: 0, Y: 0})
+	require.Contains(t, dots, engine.Dot{X: 18, Y: 0})
+	require.Contains(t, dots, engine.Dot{X: 0, Y: 18})
+	require.Contains(t, dots, engine.Dot{X: 19, Y: 19})
+	require.Contains(t, dots, engine.Dot{X: 2, Y: 0})
+	require.NotContains(t, dots, engine.Dot{X: 2, Y: 2})
+}
+
+func Test_Bomb_Detonate_DestroysObjectsInArea(t *testing.T) {
+	w, err := world.NewWorld(20, 20)
+	require.Nil(t, err)
+
+	bomb := newTestBomb(t, w, engine.Dot{X: 10, Y: 10})
+	chained := newTestBomb(t, w, engine.Dot{X: 12, Y: 10})
+
+	c, err := corpse.NewCorpse(w, engine.Location{{X: 10, Y: 11}, {X: 10, Y: 15}})
+	require.Nil(t, err)
+
+	bomb.Detonate()
+
+	require.Nil(t, w.GetObjectByDot(engine.Dot{X: 10, Y: 10}))
+	require.Nil(t, w.GetObjectByDot(engine.Dot{X: 12, Y: 10}))
+	require.Nil(t, w.GetObjectByDot(engine.Dot{X: 10, Y: 11}))
+	require.True(t, chained.exploded)
+	require.Equal(t, c, w.GetObjectByDot(engine.Dot{X: 10, Y: 15}))
+}
+
+func Test_collideBomb_CarrierPicksUpUnarmedBomb(t *testing.T) {
+	w, err := world.NewWorld(20, 20)
+	require.Nil(t, err)
+
+	dot := engine.Dot{X: 5, Y: 5}
+	bomb := newTestBomb(t, w, dot)
+	carrier := &testCarrier{limit: 1}
+
+	collision := collideBomb(bomb, carrier, dot)
+
+	require.Equal(t, registry.CollisionPass, collision.Action)
+	require.Equal(t, []interface{}{bomb}, carrier.items)
+	require.Nil(t, w.GetObjectByDot(dot))
+}
+
+func Test_collideBomb_FullCarrierSkipsUnarmedBomb(t *testing.T) {
+	w, err := world.NewWorld(20, 20)
+	require.Nil(t, err)
+
+	dot := engine.Dot{X: 5, Y: 5}
+	bomb := newTestBomb(t, w, dot)
+	carrier := &testCarrier{limit: 0}
+
+	collision := collideBomb(bomb, carrier, dot)
+
+	require.Equal(t, registry.CollisionSkip, collision.Action)
+	require.Equal(t, bomb, w.GetObjectByDot(dot))
+}
+
+func Test_collideBomb_ArmedBombKillsHitter(t *testing.T) {
+	w, err := world.NewWorld(20, 20)
+	require.Nil(t, err)
+
+	dot := engine.Dot{X: 5, Y: 5}
+	bomb := newTestBomb(t, w, dot)
+	bomb.armed = true
+	carrier := &testCarrier{limit: 1}
+
+	collision := collideBomb(bomb, carrier, dot)
+
+	require.Equal(t, registry.CollisionDie, collision.Action)
+	require.Empty(t, carrier.items)
+	require.True(t, bomb.exploded)
+}
+
+func Test_Bomb_Detonate_KillsByOwner(t *testing.T) {
+	w, err := world.NewWorld(20, 20)
+	require.Nil(t, err)
+
+	prey := &testPrey{}
+	require.Nil(t, w.CreateObject(prey, engine.Location{{X: 10, Y: 11}}))
+
+	owner := &struct{ name string }{"owner"}
+	bomb, err := NewArmedBomb(make(chan struct{}), w, engine.Dot{X: 10, Y: 10}, owner)
+	require.Nil(t, err)
+
+	bomb.Detonate()
+
+	require.Equal(t, owner, prey.killer)
+}
@@ -0,0 +1,107 @@
+package bomb
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/objects/registry"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+const chanBombObserverEventsBuffer = 32
+
+// Playground area per one unarmed bomb
+const oneBombArea = 500
+
+// Options of bomb object type
+const (
+	optionCount = "count"
+)
+
+type ErrInvalidOption struct {
+	Option string
+	Value  string
+}
+
+func (e *ErrInvalidOption) Error() string {
+	return fmt.Sprintf("invalid bomb option %s: %q", e.Option, e.Value)
+}
+
+// Observer keeps count of unarmed bombs on playground
+type Observer struct {
+	// Count of unarmed bombs. Zero count means one bomb per oneBombArea dots
+	count int
+}
+
+func NewObserver(options registry.Options) (registry.Observer, error) {
+	observer := Observer{}
+
+	for option, value := range options {
+		switch option {
+		case optionCount:
+			count, err := strconv.Atoi(value)
+			if err != nil || count <= 0 {
+				return nil, &ErrInvalidOption{option, value}
+			}
+			observer.count = count
+		default:
+			return nil, &ErrInvalidOption{option, value}
+		}
+	}
+
+	return observer, nil
+}
+
+func (o Observer) Observe(stop <-chan struct{}, w *world.World, logger logrus.FieldLogger) {
+	go func() {
+		chEvents := w.Events(stop, chanBombObserverEventsBuffer)
+
+		o.refill(w, logger)
+
+		for {
+			select {
+			case event, ok := <-chEvents:
+				if !ok {
+					return
+				}
+				if event.Type == world.EventTypeObjectDelete {
+					if _, ok := event.Payload.(*Bomb); ok {
+						o.refill(w, logger)
+					}
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+func (o Observer) target(size uint16) int {
+	if o.count > 0 {
+		return o.count
+	}
+	if target := int(size) / oneBombArea; target > 0 {
+		return target
+	}
+	return 1
+}
+
+// refill creates missing unarmed bombs
+func (o Observer) refill(w *world.World, logger logrus.FieldLogger) {
+	var bombs int
+
+	for _, object := range w.GetObjects() {
+		if bomb, ok := object.(*Bomb); ok && !bomb.Armed() {
+			bombs++
+		}
+	}
+
+	for missing := o.target(w.Size()) - bombs; missing > 0; missing-- {
+		if _, err := NewBomb(w); err != nil {
+			logger.WithError(err).Error("cannot create bomb")
+			return
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package bomb
+
+import (
+	"sync"
+
+	"github.com/pquerna/ffjson/ffjson"
+	"github.com/satori/go.uuid"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects"
+	"github.com/ivan1993spb/snake-server/objects/registry"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+func init() {
+	registry.Register(registry.Type{
+		Label:  bombTypeLabel,
+		Object: &Bomb{},
+		Snapshot: func(object interface{}) ([]byte, error) {
+			b := object.(*Bomb)
+			b.mux.RLock()
+			defer b.mux.RUnlock()
+			return ffjson.Marshal(&snapshot{
+				Dot:   b.dot,
+				Armed: b.armed,
+			})
+		},
+		Restore:     restoreBomb,
+		Collide:     collideBomb,
+		NewObserver: NewObserver,
+	})
+}
+
+type snapshot struct {
+	Dot   engine.Dot `json:"dot"`
+	Armed bool       `json:"armed"`
+}
+
+func restoreBomb(stop <-chan struct{}, world *world.World, data []byte) (interface{}, error) {
+	var s snapshot
+	if err := ffjson.Unmarshal(data, &s); err != nil {
+		return nil, ErrCreateBomb(err.Error())
+	}
+
+	if s.Armed {
+		return NewArmedBomb(stop, world, s.Dot, nil)
+	}
+
+	bomb := &Bomb{
+		uuid:  uuid.Must(uuid.NewV4()).String(),
+		world: world,
+		dot:   s.Dot,
+		mux:   &sync.RWMutex{},
+	}
+
+	if err := world.CreateObject(bomb, engine.Location{s.Dot}); err != nil {
+		return nil, ErrCreateBomb(err.Error())
+	}
+
+	return bomb, nil
+}
+
+// collideBomb lets carriers pick up unarmed bombs. Hitters which cannot pick up unarmed bomb skip the move. Hitting
+// armed bomb detonates it
+func collideBomb(object, hitter interface{}, dot engine.Dot) registry.Collision {
+	b := object.(*Bomb)
+
+	if b.Armed() {
+		b.Detonate()
+		return registry.Collision{
+			Action: registry.CollisionDie,
+		}
+	}
+
+	if carrier, ok := hitter.(objects.Carrier); ok && b.take(carrier) {
+		return registry.Collision{
+			Action: registry.CollisionPass,
+		}
+	}
+
+	return registry.Collision{
+		Action: registry.CollisionSkip,
+	}
+}
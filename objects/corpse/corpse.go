@@ -32,7 +32,7 @@ type Corpse struct {
 type ErrCreateCorpse string
 
 func (e ErrCreateCorpse) Error() string {
-	return "error on corpse creation: " + e.Error()
+	return "error on corpse creation: " + string(e)
 }
 
 // Corpse are created when a snake dies
@@ -70,6 +70,12 @@ func (c *Corpse) String() string {
 	return fmt.Sprint("corpse ", c.location)
 }
 
+func (c *Corpse) GetLocation() engine.Location {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	return c.location.Copy()
+}
+
 func (c *Corpse) NutritionalValue(dot engine.Dot) uint16 {
 	c.mux.Lock()
 	defer c.mux.Unlock()
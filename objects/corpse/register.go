@@ -0,0 +1,39 @@
+package corpse
+
+import (
+	"github.com/pquerna/ffjson/ffjson"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects/registry"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+func init() {
+	registry.Register(registry.Type{
+		Label:  corpseTypeLabel,
+		Object: &Corpse{},
+		Snapshot: func(object interface{}) ([]byte, error) {
+			c := object.(*Corpse)
+			c.mux.RLock()
+			defer c.mux.RUnlock()
+			return ffjson.Marshal(c.location)
+		},
+		Restore: restoreCorpse,
+		Collide: registry.CollideFood,
+	})
+}
+
+func restoreCorpse(stop <-chan struct{}, world *world.World, data []byte) (interface{}, error) {
+	var location engine.Location
+	if err := ffjson.Unmarshal(data, &location); err != nil {
+		return nil, ErrCreateCorpse(err.Error())
+	}
+
+	c, err := NewCorpse(world, location)
+	if err != nil {
+		return nil, err
+	}
+	c.Run(stop)
+
+	return c, nil
+}
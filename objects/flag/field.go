@@ -0,0 +1,143 @@
+package flag
+
+import (
+	"sync"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/teams"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+// Bases are squares with side baseSize
+const baseSize = 5
+
+const chanCapturesBuffer = 32
+
+// Base positions in sixths of playground width and height. Teams of two-team game get opposite corners
+var basePositions = [teams.MaxTeams][2]uint16{
+	{1, 1},
+	{5, 5},
+	{5, 1},
+	{1, 5},
+}
+
+type ErrCreateField string
+
+func (e ErrCreateField) Error() string {
+	return "cannot create field: " + string(e)
+}
+
+// Capture is sent when carrier brings enemy flag to base of its team
+type Capture struct {
+	Flag    *Flag
+	Team    *teams.Team
+	Carrier interface{}
+}
+
+// Base is zone of team where team flag stands and where enemy flags are captured
+type Base struct {
+	Team *teams.Team
+	Rect engine.Rect
+}
+
+// Field contains bases and flags of capture-the-flag game
+type Field struct {
+	world      *world.World
+	bases      []Base
+	flags      []*Flag
+	chCaptures chan Capture
+	mux        *sync.RWMutex
+}
+
+// NewField creates bases and places flags of passed teams
+func NewField(world *world.World, teamsList []*teams.Team) (*Field, error) {
+	if len(teamsList) > teams.MaxTeams {
+		return nil, ErrCreateField("too many teams")
+	}
+
+	field := &Field{
+		world:      world,
+		bases:      make([]Base, len(teamsList)),
+		flags:      make([]*Flag, 0, len(teamsList)),
+		chCaptures: make(chan Capture, chanCapturesBuffer),
+		mux:        &sync.RWMutex{},
+	}
+
+	for i, team := range teamsList {
+		field.bases[i] = Base{
+			Team: team,
+			Rect: baseRect(world.Width(), world.Height(), i),
+		}
+	}
+
+	for _, team := range teamsList {
+		flag, err := newFlag(field, team)
+		if err != nil {
+			return nil, ErrCreateField(err.Error())
+		}
+		field.flags = append(field.flags, flag)
+	}
+
+	return field, nil
+}
+
+func baseRect(width, height uint8, i int) engine.Rect {
+	x := uint16(width) * basePositions[i][0] / 6
+	y := uint16(height) * basePositions[i][1] / 6
+	return engine.NewRect(clampBase(x, width), clampBase(y, height), baseSize, baseSize)
+}
+
+func clampBase(center uint16, side uint8) uint8 {
+	if center < baseSize/2 {
+		return 0
+	}
+	if start := center - baseSize/2; start+baseSize <= uint16(side) {
+		return uint8(start)
+	}
+	return side - baseSize
+}
+
+// Base returns base of passed team
+func (f *Field) Base(team *teams.Team) (engine.Rect, bool) {
+	for _, base := range f.bases {
+		if base.Team == team {
+			return base.Rect, true
+		}
+	}
+	return engine.Rect{}, false
+}
+
+// Bases returns bases of all teams
+func (f *Field) Bases() []Base {
+	bases := make([]Base, len(f.bases))
+	copy(bases, f.bases)
+	return bases
+}
+
+// Flags returns flags of all teams
+func (f *Field) Flags() []*Flag {
+	f.mux.RLock()
+	defer f.mux.RUnlock()
+	flags := make([]*Flag, len(f.flags))
+	copy(flags, f.flags)
+	return flags
+}
+
+// Captures returns channel of flag captures
+func (f *Field) Captures() <-chan Capture {
+	return f.chCaptures
+}
+
+func (f *Field) capture(capture Capture) {
+	select {
+	case f.chCaptures <- capture:
+	default:
+	}
+}
+
+// Reset returns all flags home
+func (f *Field) Reset() {
+	for _, flag := range f.Flags() {
+		flag.ReturnHome()
+	}
+}
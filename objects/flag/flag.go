@@ -0,0 +1,185 @@
+package flag
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pquerna/ffjson/ffjson"
+	"github.com/satori/go.uuid"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects"
+	"github.com/ivan1993spb/snake-server/teams"
+)
+
+const flagTypeLabel = "flag"
+
+// Flag stands on base of its team until an enemy picks it up
+type Flag struct {
+	uuid  string
+	field *Field
+	team  *teams.Team
+	dot   engine.Dot
+	// placed is true if flag is on playground
+	placed bool
+	home   bool
+	// carrier is object which holds the flag
+	carrier interface{}
+	mux     *sync.RWMutex
+}
+
+type ErrCreateFlag string
+
+func (e ErrCreateFlag) Error() string {
+	return "cannot create flag: " + string(e)
+}
+
+func newFlag(field *Field, team *teams.Team) (*Flag, error) {
+	flag := &Flag{
+		uuid:  uuid.Must(uuid.NewV4()).String(),
+		field: field,
+		team:  team,
+		mux:   &sync.RWMutex{},
+	}
+
+	if err := flag.ReturnHome(); err != nil {
+		return nil, err
+	}
+
+	return flag, nil
+}
+
+func (f *Flag) Team() *teams.Team {
+	return f.team
+}
+
+func (f *Flag) String() string {
+	f.mux.RLock()
+	defer f.mux.RUnlock()
+	return fmt.Sprintf("flag of %s %s", f.team, f.dot)
+}
+
+// AtHome returns true if flag stands on base of its team
+func (f *Flag) AtHome() bool {
+	f.mux.RLock()
+	defer f.mux.RUnlock()
+	return f.home
+}
+
+// ReturnHome places flag on base of its team
+func (f *Flag) ReturnHome() error {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	return f.unsafeReturnHome()
+}
+
+func (f *Flag) unsafeReturnHome() error {
+	if f.placed && f.home {
+		return nil
+	}
+
+	base, _ := f.field.Base(f.team)
+	dots := append([]engine.Dot{base.Center()}, base.Dots()...)
+
+	for _, dot := range dots {
+		var ok bool
+		if f.placed {
+			ok = f.field.world.UpdateObject(f, engine.Location{f.dot}, engine.Location{dot}) == nil
+		} else {
+			ok = f.field.world.CreateObject(f, engine.Location{dot}) == nil
+		}
+
+		if ok {
+			f.dot = dot
+			f.placed = true
+			f.home = true
+			f.carrier = nil
+			return nil
+		}
+	}
+
+	return ErrCreateFlag("no free dots on base")
+}
+
+// take removes flag from playground if carrier accepts it
+func (f *Flag) take(carrier objects.Carrier) bool {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	if !f.placed || !carrier.PickUp(f) {
+		return false
+	}
+
+	f.field.world.DeleteObject(f, engine.Location{f.dot})
+	f.placed = false
+	f.home = false
+	f.carrier = carrier
+
+	return true
+}
+
+// Carry is called by carrier after each move. Carry captures the flag if carrier's head is on base of carrier's
+// team. It returns false if carrier does not hold the flag anymore
+func (f *Flag) Carry(carrier interface{}, head engine.Dot, team *teams.Team) bool {
+	f.mux.Lock()
+
+	if f.carrier != carrier {
+		f.mux.Unlock()
+		return false
+	}
+
+	base, ok := f.field.Base(team)
+	if !ok || !base.ContainsDot(head) {
+		f.mux.Unlock()
+		return true
+	}
+
+	f.unsafeReturnHome()
+	f.mux.Unlock()
+
+	f.field.capture(Capture{
+		Flag:    f,
+		Team:    team,
+		Carrier: carrier,
+	})
+
+	return false
+}
+
+// Drop places carried flag on passed dot. If the dot is occupied the flag returns home
+func (f *Flag) Drop(carrier interface{}, dot engine.Dot) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	if f.carrier != carrier {
+		return
+	}
+
+	f.carrier = nil
+
+	if err := f.field.world.CreateObject(f, engine.Location{dot}); err == nil {
+		f.dot = dot
+		f.placed = true
+		return
+	}
+
+	f.unsafeReturnHome()
+}
+
+func (f *Flag) MarshalJSON() ([]byte, error) {
+	f.mux.RLock()
+	defer f.mux.RUnlock()
+	return ffjson.Marshal(&flag{
+		UUID: f.uuid,
+		Dot:  f.dot,
+		Type: flagTypeLabel,
+		Team: f.team.Name(),
+	})
+}
+
+type flag struct {
+	UUID string     `json:"uuid"`
+	Dot  engine.Dot `json:"dot"`
+	Type string     `json:"type"`
+	Team string     `json:"team"`
+}
@@ -0,0 +1,113 @@
+package flag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects/registry"
+	"github.com/ivan1993spb/snake-server/teams"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+type testCarrier struct {
+	team *teams.Team
+	flag *Flag
+}
+
+func (c *testCarrier) GetTeam() *teams.Team {
+	return c.team
+}
+
+func (c *testCarrier) PickUp(item interface{}) bool {
+	if flag, ok := item.(*Flag); ok && c.flag == nil {
+		c.flag = flag
+		return true
+	}
+	return false
+}
+
+func newTestField(t *testing.T) (*world.World, *Field, *teams.Team, *teams.Team) {
+	w, err := world.NewWorld(60, 60)
+	require.Nil(t, err)
+
+	gameTeams, err := teams.NewTeams(2, true)
+	require.Nil(t, err)
+
+	field, err := NewField(w, gameTeams.List())
+	require.Nil(t, err)
+
+	red, _ := gameTeams.Get("red")
+	blue, _ := gameTeams.Get("blue")
+
+	return w, field, red, blue
+}
+
+func Test_NewField_PlacesFlagsOnBases(t *testing.T) {
+	w, field, red, blue := newTestField(t)
+
+	redBase, ok := field.Base(red)
+	require.True(t, ok)
+	require.Equal(t, engine.NewRect(8, 8, baseSize, baseSize), redBase)
+
+	blueBase, ok := field.Base(blue)
+	require.True(t, ok)
+	require.Equal(t, engine.NewRect(48, 48, baseSize, baseSize), blueBase)
+
+	flags := field.Flags()
+	require.Len(t, flags, 2)
+	require.Equal(t, flags[0], w.GetObjectByDot(redBase.Center()))
+	require.Equal(t, flags[1], w.GetObjectByDot(blueBase.Center()))
+	require.True(t, flags[0].AtHome())
+}
+
+func Test_Flag_CaptureByEnemy(t *testing.T) {
+	w, field, red, blue := newTestField(t)
+	redFlag := field.Flags()[0]
+	redBase, _ := field.Base(red)
+	blueBase, _ := field.Base(blue)
+
+	teammate := &testCarrier{team: red}
+	collision := collideFlag(redFlag, teammate, redBase.Center())
+	require.Equal(t, registry.CollisionSkip, collision.Action)
+	require.Nil(t, teammate.flag)
+
+	enemy := &testCarrier{team: blue}
+	collision = collideFlag(redFlag, enemy, redBase.Center())
+	require.Equal(t, registry.CollisionPass, collision.Action)
+	require.Equal(t, redFlag, enemy.flag)
+	require.Nil(t, w.GetObjectByDot(redBase.Center()))
+
+	require.True(t, redFlag.Carry(enemy, engine.Dot{X: 30, Y: 30}, blue))
+	require.False(t, redFlag.Carry(enemy, blueBase.Dot(0), blue))
+
+	capture := <-field.Captures()
+	require.Equal(t, blue, capture.Team)
+	require.Equal(t, redFlag, capture.Flag)
+	require.Equal(t, enemy, capture.Carrier)
+	require.True(t, redFlag.AtHome())
+	require.Equal(t, redFlag, w.GetObjectByDot(redBase.Center()))
+}
+
+func Test_Flag_DropAndReturn(t *testing.T) {
+	w, field, red, blue := newTestField(t)
+	redFlag := field.Flags()[0]
+	redBase, _ := field.Base(red)
+
+	enemy := &testCarrier{team: blue}
+	require.True(t, redFlag.take(enemy))
+
+	dot := engine.Dot{X: 30, Y: 30}
+	redFlag.Drop(enemy, dot)
+	require.Equal(t, redFlag, w.GetObjectByDot(dot))
+	require.False(t, redFlag.AtHome())
+	require.False(t, redFlag.Carry(enemy, dot, blue), "flag was dropped")
+
+	teammate := &testCarrier{team: red}
+	collision := collideFlag(redFlag, teammate, dot)
+	require.Equal(t, registry.CollisionPass, collision.Action)
+	require.Nil(t, w.GetObjectByDot(dot))
+	require.True(t, redFlag.AtHome())
+	require.Equal(t, redFlag, w.GetObjectByDot(redBase.Center()))
+}
@@ -0,0 +1,44 @@
+package flag
+
+import (
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects"
+	"github.com/ivan1993spb/snake-server/objects/registry"
+	"github.com/ivan1993spb/snake-server/teams"
+)
+
+func init() {
+	registry.Register(registry.Type{
+		Label:   flagTypeLabel,
+		Object:  &Flag{},
+		Collide: collideFlag,
+	})
+}
+
+// member is implemented by objects which belong to teams
+type member interface {
+	GetTeam() *teams.Team
+}
+
+// collideFlag lets enemies pick up flag and teammates return dropped flag home. Flags never kill hitters
+func collideFlag(object, hitter interface{}, dot engine.Dot) registry.Collision {
+	f := object.(*Flag)
+
+	if m, ok := hitter.(member); ok && m.GetTeam() != nil {
+		if teams.Teammates(f.team, m.GetTeam()) {
+			if !f.AtHome() && f.ReturnHome() == nil {
+				return registry.Collision{
+					Action: registry.CollisionPass,
+				}
+			}
+		} else if carrier, ok := hitter.(objects.Carrier); ok && f.take(carrier) {
+			return registry.Collision{
+				Action: registry.CollisionPass,
+			}
+		}
+	}
+
+	return registry.Collision{
+		Action: registry.CollisionSkip,
+	}
+}
@@ -11,10 +11,25 @@ type Alive interface {
 	Kill(dot engine.Dot)
 }
 
+// Prey interface describes alive objects which can be killed by another object
+type Prey interface {
+	KilledBy(dot engine.Dot, killer interface{})
+}
+
 type Strong interface {
 	Strength(dot engine.Dot)
 }
 
+// Hard interface describes objects that can be broken by dots
+type Hard interface {
+	Break(dot engine.Dot)
+}
+
+// Carrier interface describes objects that can pick up items
+type Carrier interface {
+	PickUp(item interface{}) bool
+}
+
 // Если предмет съедобный - то он кусается Food - Bite
 // Если предмет твердый - то он ломается Hard - Break
 // Если предмет живой - Alive - Kill
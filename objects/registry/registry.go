@@ -0,0 +1,215 @@
+// Package registry keeps object types which can be placed on playground.
+//
+// Object packages register their types in init functions. Games enable selected set of registered types
+// by type labels.
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+// Observer runs spawn logic of an object type in a world
+type Observer interface {
+	Observe(stop <-chan struct{}, world *world.World, logger logrus.FieldLogger)
+}
+
+// Options contains settings of an object type passed with game configuration
+type Options map[string]string
+
+// ObserverFactory creates spawn observer for a game with passed options
+type ObserverFactory func(options Options) (Observer, error)
+
+type CollisionAction uint8
+
+const (
+	// CollisionDie means that hitter dies
+	CollisionDie CollisionAction = iota
+	// CollisionPass means that hit dot is released and hitter can occupy it
+	CollisionPass
+	// CollisionSkip means that hitter skips the move: it keeps its dots and does not occupy hit dot
+	CollisionSkip
+)
+
+// Collision is result of hitting a dot of an object
+type Collision struct {
+	Action    CollisionAction
+	Nutrition uint16
+}
+
+// CollisionFunc returns what happens when hitter hits the dot of object
+type CollisionFunc func(object, hitter interface{}, dot engine.Dot) Collision
+
+// Type describes object type
+type Type struct {
+	// Label is used in object JSON and in game configuration
+	Label string
+	// Object is any object of the type. It is used to recognize types of objects. Object must implement
+	// json.Marshaler which encodes objects of the type for clients
+	Object interface{}
+	// Snapshot returns data from which object can be restored. Snapshot and Restore are nil if objects of the type
+	// cannot be restored, for example objects created by game modes
+	Snapshot func(object interface{}) ([]byte, error)
+	// Restore creates object in world using snapshot data. Objects which run until stop is closed run until world
+	// is stopped
+	Restore func(stop <-chan struct{}, world *world.World, data []byte) (interface{}, error)
+	// Collide returns collision behavior of objects. If Collide is nil hitters die
+	Collide CollisionFunc
+	// NewObserver creates spawn observer. NewObserver may be nil if type has no spawn logic
+	NewObserver ObserverFactory
+}
+
+var (
+	types    = map[string]*Type{}
+	typesRef = map[reflect.Type]*Type{}
+	typesMux = &sync.RWMutex{}
+)
+
+// Register makes object type available by label. If Register is called twice with the same label, if type has no
+// label, object has no JSON encoder or type has only one of snapshot and restore functions, it panics
+func Register(t Type) {
+	typesMux.Lock()
+	defer typesMux.Unlock()
+
+	if t.Label == "" {
+		panic("registry: type label is empty")
+	}
+	if t.Object == nil {
+		panic("registry: object of type " + t.Label + " is nil")
+	}
+	if _, ok := t.Object.(json.Marshaler); !ok {
+		panic("registry: object of type " + t.Label + " has no JSON encoder")
+	}
+	if (t.Snapshot == nil) != (t.Restore == nil) {
+		panic("registry: snapshot codec of type " + t.Label + " is incomplete")
+	}
+	if _, dup := types[t.Label]; dup {
+		panic("registry: Register called twice for type " + t.Label)
+	}
+
+	ref := reflect.TypeOf(t.Object)
+	if _, dup := typesRef[ref]; dup {
+		panic("registry: Register called twice for object of type " + t.Label)
+	}
+
+	types[t.Label] = &t
+	typesRef[ref] = &t
+}
+
+// Get returns type by label
+func Get(label string) (Type, bool) {
+	typesMux.RLock()
+	defer typesMux.RUnlock()
+	if t, ok := types[label]; ok {
+		return *t, true
+	}
+	return Type{}, false
+}
+
+// TypeOf returns type of passed object
+func TypeOf(object interface{}) (Type, bool) {
+	typesMux.RLock()
+	defer typesMux.RUnlock()
+	if t, ok := typesRef[reflect.TypeOf(object)]; ok {
+		return *t, true
+	}
+	return Type{}, false
+}
+
+// Labels returns sorted labels of all registered types
+func Labels() []string {
+	typesMux.RLock()
+	defer typesMux.RUnlock()
+	labels := make([]string, 0, len(types))
+	for label := range types {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// Collide returns collision behavior of object. Objects of unknown types are hard unless they are food
+func Collide(object, hitter interface{}, dot engine.Dot) Collision {
+	if t, ok := TypeOf(object); ok {
+		if t.Collide != nil {
+			return t.Collide(object, hitter, dot)
+		}
+		return Collision{
+			Action: CollisionDie,
+		}
+	}
+	if _, ok := object.(objects.Food); ok {
+		return CollideFood(object, hitter, dot)
+	}
+	return Collision{
+		Action: CollisionDie,
+	}
+}
+
+// CollideFood is collision function for edible objects: hitter bites food
+func CollideFood(object, hitter interface{}, dot engine.Dot) Collision {
+	if food, ok := object.(objects.Food); ok {
+		return Collision{
+			Action:    CollisionPass,
+			Nutrition: food.NutritionalValue(dot),
+		}
+	}
+	return Collision{
+		Action: CollisionDie,
+	}
+}
+
+var ErrUnknownType = errors.New("unknown object type")
+
+// Snapshot keeps object state
+type Snapshot struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+var ErrRestoreNotSupported = errors.New("object type does not support restore")
+
+// Restorable returns true if objects of the type can be saved to snapshots and restored
+func (t Type) Restorable() bool {
+	return t.Snapshot != nil && t.Restore != nil
+}
+
+// TakeSnapshot returns snapshot of object
+func TakeSnapshot(object interface{}) (*Snapshot, error) {
+	t, ok := TypeOf(object)
+	if !ok {
+		return nil, ErrUnknownType
+	}
+	if !t.Restorable() {
+		return nil, ErrRestoreNotSupported
+	}
+	data, err := t.Snapshot(object)
+	if err != nil {
+		return nil, err
+	}
+	return &Snapshot{
+		Type: t.Label,
+		Data: data,
+	}, nil
+}
+
+// RestoreSnapshot creates object by snapshot in world
+func RestoreSnapshot(stop <-chan struct{}, world *world.World, snapshot *Snapshot) (interface{}, error) {
+	t, ok := Get(snapshot.Type)
+	if !ok {
+		return nil, ErrUnknownType
+	}
+	if !t.Restorable() {
+		return nil, ErrRestoreNotSupported
+	}
+	return t.Restore(stop, world, snapshot.Data)
+}
@@ -0,0 +1,120 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+type testObject struct {
+	dot engine.Dot
+}
+
+func (*testObject) MarshalJSON() ([]byte, error) {
+	return []byte(`{"type":"test_object"}`), nil
+}
+
+type testUnrestorableObject struct{}
+
+func (*testUnrestorableObject) MarshalJSON() ([]byte, error) {
+	return []byte(`{"type":"test_unrestorable_object"}`), nil
+}
+
+type testFood struct{}
+
+func (testFood) NutritionalValue(dot engine.Dot) uint16 {
+	return 7
+}
+
+func init() {
+	Register(Type{
+		Label:  "test_object",
+		Object: &testObject{},
+		Snapshot: func(object interface{}) ([]byte, error) {
+			return object.(*testObject).dot.MarshalJSON()
+		},
+		Restore: func(stop <-chan struct{}, w *world.World, data []byte) (interface{}, error) {
+			object := &testObject{}
+			if err := object.dot.UnmarshalJSON(data); err != nil {
+				return nil, err
+			}
+			return object, w.CreateObject(object, engine.Location{object.dot})
+		},
+		Collide: func(object, hitter interface{}, dot engine.Dot) Collision {
+			return Collision{
+				Action: CollisionPass,
+			}
+		},
+	})
+	Register(Type{
+		Label:  "test_unrestorable_object",
+		Object: &testUnrestorableObject{},
+	})
+}
+
+func Test_Register_PanicsOnDuplicate(t *testing.T) {
+	require.Panics(t, func() {
+		Register(Type{
+			Label:  "test_object",
+			Object: &testObject{},
+		})
+	})
+	require.Panics(t, func() {
+		Register(Type{
+			Label:  "test_object_without_encoder",
+			Object: &testFood{},
+		})
+	})
+	require.Panics(t, func() {
+		Register(Type{
+			Label:    "test_object_without_restore",
+			Object:   &testObject{},
+			Snapshot: func(object interface{}) ([]byte, error) { return nil, nil },
+		})
+	})
+}
+
+func Test_TypeOf_ReturnsRegisteredType(t *testing.T) {
+	objectType, ok := TypeOf(&testObject{})
+	require.True(t, ok)
+	require.Equal(t, "test_object", objectType.Label)
+
+	_, ok = TypeOf(testObject{})
+	require.False(t, ok)
+
+	require.Contains(t, Labels(), "test_object")
+}
+
+func Test_Collide_UsesTypeCollisionAndFallbacks(t *testing.T) {
+	require.Equal(t, Collision{Action: CollisionPass}, Collide(&testObject{}, nil, engine.Dot{}))
+	require.Equal(t, Collision{Action: CollisionPass, Nutrition: 7}, Collide(testFood{}, nil, engine.Dot{}))
+	require.Equal(t, Collision{Action: CollisionDie}, Collide(struct{}{}, nil, engine.Dot{}))
+}
+
+func Test_Snapshot_RestoresObject(t *testing.T) {
+	w, err := world.NewWorld(10, 10)
+	require.Nil(t, err)
+
+	snapshot, err := TakeSnapshot(&testObject{dot: engine.Dot{X: 3, Y: 4}})
+	require.Nil(t, err)
+	require.Equal(t, "test_object", snapshot.Type)
+
+	object, err := RestoreSnapshot(nil, w, snapshot)
+	require.Nil(t, err)
+	require.Equal(t, engine.Dot{X: 3, Y: 4}, object.(*testObject).dot)
+	require.Equal(t, object, w.GetObjectByDot(engine.Dot{X: 3, Y: 4}))
+
+	_, err = RestoreSnapshot(nil, w, &Snapshot{Type: "unknown"})
+	require.Equal(t, ErrUnknownType, err)
+}
+
+func Test_TakeSnapshot_FailsOnUnrestorableObject(t *testing.T) {
+	_, err := TakeSnapshot(&testUnrestorableObject{})
+	require.Equal(t, ErrRestoreNotSupported, err)
+
+	_, err = RestoreSnapshot(nil, nil, &Snapshot{Type: "test_unrestorable_object"})
+	require.Equal(t, ErrRestoreNotSupported, err)
+}
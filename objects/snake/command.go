@@ -0,0 +1,22 @@
+package snake
+
+// ErrCommand is error of snake command. Value of error is machine-readable code
+type ErrCommand string
+
+func (e ErrCommand) Error() string {
+	return "cannot execute command: " + string(e)
+}
+
+// Code returns machine-readable code of error
+func (e ErrCommand) Code() string {
+	return string(e)
+}
+
+const (
+	ErrUnknownCommand   = ErrCommand("unknown_command")
+	ErrInvalidDirection = ErrCommand("invalid_direction")
+	ErrReverseDirection = ErrCommand("reverse_direction")
+	ErrTurnQueueFull    = ErrCommand("turn_queue_full")
+	ErrTooShortToBoost  = ErrCommand("too_short_to_boost")
+	ErrNoBombs          = ErrCommand("no_bombs")
+)
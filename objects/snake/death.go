@@ -0,0 +1,128 @@
+package snake
+
+import (
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects/bomb"
+	"github.com/ivan1993spb/snake-server/objects/wall"
+)
+
+// DeathCause is reason of snake death
+type DeathCause uint8
+
+const (
+	DeathCauseUnknown DeathCause = iota
+	DeathCauseWall
+	DeathCauseBorder
+	DeathCauseBody
+	DeathCauseHeadOn
+	DeathCauseSelf
+	DeathCauseBomb
+	DeathCauseDisconnect
+	DeathCauseReset
+)
+
+var deathCauseLabels = map[DeathCause]string{
+	DeathCauseUnknown:    "unknown",
+	DeathCauseWall:       "wall",
+	DeathCauseBorder:     "border",
+	DeathCauseBody:       "body",
+	DeathCauseHeadOn:     "head_on",
+	DeathCauseSelf:       "self",
+	DeathCauseBomb:       "bomb",
+	DeathCauseDisconnect: "disconnect",
+	DeathCauseReset:      "reset",
+}
+
+func (c DeathCause) String() string {
+	if label, ok := deathCauseLabels[c]; ok {
+		return label
+	}
+	return "unknown"
+}
+
+func (c DeathCause) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + c.String() + `"`), nil
+}
+
+// Death describes why snake died and who killed it
+type Death struct {
+	Cause    DeathCause `json:"cause"`
+	Snake    string     `json:"snake"`
+	Player   string     `json:"player,omitempty"`
+	Nickname string     `json:"nickname"`
+	// Killer is uuid of snake which killed the snake
+	Killer         string `json:"killer,omitempty"`
+	KillerPlayer   string `json:"killer_player,omitempty"`
+	KillerNickname string `json:"killer_nickname,omitempty"`
+	// Length is final length of snake
+	Length uint16 `json:"length"`
+}
+
+// setDeath saves cause of death and killer. Killer may be nil. Only the first cause is saved
+func (s *Snake) setDeath(cause DeathCause, killer *Snake) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.unsafeSetDeath(cause, killer)
+}
+
+func (s *Snake) unsafeSetDeath(cause DeathCause, killer *Snake) {
+	if s.deathCause != DeathCauseUnknown {
+		return
+	}
+	s.deathCause = cause
+	s.killer = killer
+}
+
+// collisionDeathCause returns cause of death of snake which hits passed object on dot and the killer if there is one
+func (s *Snake) collisionDeathCause(object interface{}, dot engine.Dot) (DeathCause, *Snake) {
+	var other *Snake
+
+	switch object := object.(type) {
+	case *Snake:
+		other = object
+	case *wall.Wall:
+		return DeathCauseWall, nil
+	case *bomb.Bomb:
+		// Snake which hits armed bomb is killed by owner of the bomb
+		if owner, ok := object.Owner().(*Snake); ok && owner != s {
+			return DeathCauseBomb, owner
+		}
+		return DeathCauseBomb, nil
+	default:
+		return DeathCauseUnknown, nil
+	}
+
+	if other == s {
+		return DeathCauseSelf, nil
+	}
+	if location := other.GetLocation(); len(location) > 0 && location[0].Equals(dot) {
+		return DeathCauseHeadOn, other
+	}
+	return DeathCauseBody, other
+}
+
+// Death returns description of snake death. Cause is unknown if snake is alive
+func (s *Snake) Death() Death {
+	s.mux.RLock()
+	death := Death{
+		Cause:  s.deathCause,
+		Snake:  s.uuid,
+		Length: s.length,
+	}
+	if s.profile != nil {
+		death.Player = s.profile.ID
+		death.Nickname = s.profile.Nickname
+	}
+	killer := s.killer
+	s.mux.RUnlock()
+
+	if killer != nil {
+		death.Killer = killer.GetUUID()
+		if p := killer.GetProfile(); p != nil {
+			death.KillerPlayer = p.ID
+			death.KillerNickname = p.Nickname
+		}
+	}
+
+	return death
+}
@@ -1,22 +1,27 @@
-package observers
+package snake
 
 import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/ivan1993spb/snake-server/objects/corpse"
-	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/objects/registry"
 	"github.com/ivan1993spb/snake-server/world"
 )
 
 const chanSnakeObserverEventsBuffer = 32
 
-type SnakeObserver struct{}
+// Observer creates corpses of dead snakes
+type Observer struct{}
 
-func (SnakeObserver) Observe(stop <-chan struct{}, w *world.World, logger logrus.FieldLogger) {
+func NewObserver(options registry.Options) (registry.Observer, error) {
+	return Observer{}, nil
+}
+
+func (Observer) Observe(stop <-chan struct{}, w *world.World, logger logrus.FieldLogger) {
 	go func() {
 		for event := range w.Events(stop, chanSnakeObserverEventsBuffer) {
 			if event.Type == world.EventTypeObjectDelete {
-				if s, ok := event.Payload.(*snake.Snake); ok {
+				if s, ok := event.Payload.(*Snake); ok {
 					if c, err := corpse.NewCorpse(w, s.GetLocation()); err != nil {
 						logger.WithError(err).Error("cannot create corpse")
 					} else {
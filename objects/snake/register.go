@@ -0,0 +1,42 @@
+package snake
+
+import (
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects/registry"
+	"github.com/ivan1993spb/snake-server/teams"
+)
+
+func init() {
+	registry.Register(registry.Type{
+		Label:       snakeTypeLabel,
+		Object:      &Snake{},
+		Collide:     collideSnake,
+		NewObserver: NewObserver,
+	})
+}
+
+// collideSnake kills hitter. Teammates wait in front of each other if friendly fire is disabled. The hit snake is
+// credited with a kill when the hitter actually dies
+func collideSnake(object, hitter interface{}, dot engine.Dot) registry.Collision {
+	s := object.(*Snake)
+
+	// Hitters wait in front of protected snake
+	if s.Protected() {
+		return registry.Collision{
+			Action: registry.CollisionSkip,
+		}
+	}
+
+	if h, ok := hitter.(*Snake); ok && h != s {
+		team := s.GetTeam()
+		if teams.Teammates(team, h.GetTeam()) && !team.FriendlyFire() {
+			return registry.Collision{
+				Action: registry.CollisionSkip,
+			}
+		}
+	}
+
+	return registry.Collision{
+		Action: registry.CollisionDie,
+	}
+}
@@ -11,7 +11,12 @@ import (
 	"github.com/satori/go.uuid"
 
 	"github.com/ivan1993spb/snake-server/engine"
-	"github.com/ivan1993spb/snake-server/objects"
+	"github.com/ivan1993spb/snake-server/objects/bomb"
+	"github.com/ivan1993spb/snake-server/objects/corpse"
+	"github.com/ivan1993spb/snake-server/objects/flag"
+	"github.com/ivan1993spb/snake-server/objects/registry"
+	"github.com/ivan1993spb/snake-server/profile"
+	"github.com/ivan1993spb/snake-server/teams"
 	"github.com/ivan1993spb/snake-server/world"
 )
 
@@ -24,6 +29,27 @@ const (
 	snakeTypeLabel      = "snake"
 )
 
+const (
+	// Boost divides delay between snake moves
+	snakeBoostSpeedFactor = 2
+	// Time for which boost command accelerates snake
+	snakeBoostDuration = time.Second * 2
+	// Boosted snake drops one tail dot per snakeBoostDrainMoves moves
+	snakeBoostDrainMoves = 2
+	// Snake cannot be drained shorter than snakeBoostMinLength
+	snakeBoostMinLength = snakeStartLength
+)
+
+const (
+	// Snake cannot carry more than snakeMaxBombs bombs
+	snakeMaxBombs = 3
+	// Snake cut shorter than snakeKillMinLength dies
+	snakeKillMinLength = 2
+)
+
+// Count of attempts to find place for snake far from heads of other snakes
+const snakeSafeLocateRetries = 32
+
 type Command string
 
 const (
@@ -31,6 +57,8 @@ const (
 	CommandToEast  Command = "east"
 	CommandToSouth Command = "south"
 	CommandToWest  Command = "west"
+	CommandBoost   Command = "boost"
+	CommandBomb    Command = "bomb"
 )
 
 var snakeCommands = map[Command]engine.Direction{
@@ -51,12 +79,46 @@ type Snake struct {
 
 	direction engine.Direction
 
+	boostedUntil time.Time
+	boostMoves   uint16
+
+	bombs    uint8
+	dropBomb bool
+
+	carriedFlag *flag.Flag
+
+	profile *profile.Profile
+	team    *teams.Team
+	kills   uint16
+
+	chKill chan struct{}
+	killed bool
+
+	// chDone is closed when Run loop of snake exits. It is nil until snake is run
+	chDone <-chan struct{}
+
+	// Snake is invulnerable until protectedUntil
+	protectedUntil time.Time
+
+	// Frozen snake stays in place. Snake of disconnected player may be frozen
+	frozen bool
+
+	deathCause DeathCause
+	killer     *Snake
+
+	// turns contains queued directions. Snake consumes one turn per move
+	turns     []engine.Direction
+	turnQueue TurnQueue
+
+	// chMoved receives signal after each move of snake
+	chMoved chan struct{}
+
 	mux *sync.RWMutex
 }
 
-// NewSnake creates new snake
-func NewSnake(world *world.World) (*Snake, error) {
-	snake := newDefaultSnake(world)
+// NewSnake creates new snake with passed player profile. Team may be nil if game has no teams
+func NewSnake(world *world.World, profile *profile.Profile, team *teams.Team) (*Snake, error) {
+	snake := newDefaultSnake(world, profile, team)
 	location, err := snake.locate()
 	if err != nil {
 		return nil, fmt.Errorf("cannot create snake: %s", err)
@@ -71,13 +133,35 @@ func NewSnake(world *world.World) (*Snake, error) {
 	return snake, nil
 }
 
-func newDefaultSnake(world *world.World) *Snake {
+// NewSafeSnake creates new snake like NewSnake but not closer than distance dots to heads of other snakes
+func NewSafeSnake(world *world.World, profile *profile.Profile, team *teams.Team, distance uint8) (*Snake, error) {
+	snake := newDefaultSnake(world, profile, team)
+	location, err := snake.locateSafe(distance)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create snake: %s", err)
+	}
+
+	if snake.direction == engine.DirectionSouth || snake.direction == engine.DirectionEast {
+		location = location.Reverse()
+	}
+
+	snake.setLocation(location)
+
+	return snake, nil
+}
+
+func newDefaultSnake(world *world.World, profile *profile.Profile, team *teams.Team) *Snake {
 	return &Snake{
 		uuid:      uuid.Must(uuid.NewV4()).String(),
 		world:     world,
 		location:  make(engine.Location, snakeStartLength),
 		length:    snakeStartLength,
 		direction: engine.RandomDirection(),
+		profile:   profile,
+		team:      team,
+		chKill:    make(chan struct{}),
+		turnQueue: DefaultTurnQueue(),
+		chMoved:   make(chan struct{}, 1),
 		mux:       &sync.RWMutex{},
 	}
 }
@@ -94,6 +178,89 @@ func (s *Snake) locate() (engine.Location, error) {
 	return nil, errors.New("invalid direction")
 }
 
+// locateSafe creates snake on random free place which is not closer than distance dots to heads of other snakes
+func (s *Snake) locateSafe(distance uint8) (engine.Location, error) {
+	var heads []engine.Dot
+	for _, object := range s.world.GetObjects() {
+		if other, ok := object.(*Snake); ok && other != s {
+			if location := other.GetLocation(); len(location) > 0 {
+				heads = append(heads, location[0])
+			}
+		}
+	}
+
+	rw, rh := uint8(1), uint8(snakeStartLength)
+	if s.direction == engine.DirectionEast || s.direction == engine.DirectionWest {
+		rw, rh = rh, rw
+	}
+
+	for i := 0; i < snakeSafeLocateRetries; i++ {
+		rect, err := s.world.RandomRect(rw+snakeStartMargin*2, rh+snakeStartMargin*2)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(s.world.FreeDots(rect.Location())) != int(rect.DotCount()) {
+			continue
+		}
+
+		location := engine.NewRect(rect.X()+snakeStartMargin, rect.Y()+snakeStartMargin, rw, rh).Location()
+		if !farFrom(location, heads, distance) {
+			continue
+		}
+
+		if err := s.world.CreateObject(s, location); err == nil {
+			return location, nil
+		}
+	}
+
+	return nil, errors.New("cannot find safe place")
+}
+
+// farFrom returns true if all dots of location are farther than distance from heads
+func farFrom(location engine.Location, heads []engine.Dot, distance uint8) bool {
+	for _, dot := range location {
+		for _, head := range heads {
+			if dot.DistanceTo(head) <= uint16(distance) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Protect makes snake invulnerable for passed duration
+func (s *Snake) Protect(duration time.Duration) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.protectedUntil = time.Now().Add(duration)
+}
+
+// Protected returns true if snake is invulnerable
+func (s *Snake) Protected() bool {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.unsafeProtected()
+}
+
+func (s *Snake) unsafeProtected() bool {
+	return time.Now().Before(s.protectedUntil)
+}
+
+// Freeze stops or resumes movement of snake
+func (s *Snake) Freeze(frozen bool) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.frozen = frozen
+}
+
+// Frozen returns true if snake stays in place
+func (s *Snake) Frozen() bool {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.frozen
+}
+
 func (s *Snake) setLocation(location engine.Location) {
 	s.mux.Lock()
 	defer s.mux.Unlock()
@@ -106,6 +273,65 @@ func (s *Snake) GetUUID() string {
 	return s.uuid
 }
 
+func (s *Snake) GetLength() uint16 {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.length
+}
+
+func (s *Snake) GetTeam() *teams.Team {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.team
+}
+
+// GetKills returns count of snakes which died hitting the snake
+func (s *Snake) GetKills() uint16 {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.kills
+}
+
+func (s *Snake) addKill() {
+	s.mux.Lock()
+	s.kills++
+	team := s.team
+	s.mux.Unlock()
+
+	if team != nil {
+		team.AddKill()
+	}
+}
+
+func (s *Snake) GetProfile() *profile.Profile {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.profile
+}
+
+// GetDirection returns current movement direction of snake
+func (s *Snake) GetDirection() engine.Direction {
+	return s.getDirection()
+}
+
+// Moved returns channel which receives signal after each move of snake. Signals are dropped if nobody receives them
+func (s *Snake) Moved() <-chan struct{} {
+	return s.chMoved
+}
+
+func (s *Snake) notifyMoved() {
+	select {
+	case s.chMoved <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Snake) getDirection() engine.Direction {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.direction
+}
+
 func (s *Snake) setDirection(dir engine.Direction) {
 	s.mux.Lock()
 	defer s.mux.Unlock()
@@ -121,7 +347,23 @@ func (s *Snake) String() string {
 func (s *Snake) die() {
 	s.mux.RLock()
 	s.world.DeleteObject(s, engine.Location(s.location))
+	carriedFlag := s.carriedFlag
+	var head engine.Dot
+	if len(s.location) > 0 {
+		head = s.location[0]
+	}
+	killer := s.killer
 	s.mux.RUnlock()
+
+	// Killer is credited only when the snake is dead: protected hitters survive collisions
+	if killer != nil && killer != s {
+		killer.addKill()
+	}
+
+	// Carrier drops flag where it dies
+	if carriedFlag != nil {
+		carriedFlag.Drop(s, head)
+	}
 }
 
 func (s *Snake) feed(f uint16) {
@@ -141,20 +383,41 @@ func (s *Snake) strength() float32 {
 func (s *Snake) Run(stop <-chan struct{}) <-chan struct{} {
 	snakeStop := make(chan struct{})
 
+	s.mux.Lock()
+	s.chDone = snakeStop
+	s.mux.Unlock()
+
 	go func() {
-		var ticker = time.NewTicker(s.calculateDelay())
-		defer ticker.Stop()
+		var timer = time.NewTimer(s.calculateDelay())
+		defer timer.Stop()
 		defer close(snakeStop)
 		defer s.die()
 
 		for {
 			select {
-			case <-ticker.C:
+			case <-timer.C:
+				if s.Frozen() {
+					timer.Reset(s.calculateDelay())
+					continue
+				}
 				if err := s.move(); err != nil {
-					// TODO: Handle error.
+					// Cause is saved by move if snake crashed
 					return
 				}
+				if dot, ok := s.drain(); ok {
+					s.leaveTrail(dot)
+				}
+				if s.takeBombDrop() {
+					s.placeBomb()
+				}
+				s.carryFlag()
+				s.notifyMoved()
+				// Delay depends on snake length and boost
+				timer.Reset(s.calculateDelay())
+			case <-s.chKill:
+				return
 			case <-stop:
+				s.setDeath(DeathCauseDisconnect, nil)
 				return
 			}
 		}
@@ -164,6 +427,8 @@ func (s *Snake) Run(stop <-chan struct{}) <-chan struct{} {
 }
 
 func (s *Snake) move() error {
+	s.nextTurn()
+
 	// Calculate next position
 	dot, err := s.getNextHeadDot()
 	if err != nil {
@@ -171,16 +436,21 @@ func (s *Snake) move() error {
 	}
 
 	if object := s.world.GetObjectByDot(dot); object != nil {
-		if food, ok := object.(objects.Food); ok {
-			s.feed(food.NutritionalValue(dot))
-		} else {
-			//s.die()
+		collision := registry.Collide(object, s, dot)
 
+		// Snake waits in front of dots which it skips. Protected snake waits in front of dots which would kill it.
+		// Head is never moved further than the next dot, so body of snake stays solid
+		if collision.Action == registry.CollisionSkip ||
+			(collision.Action == registry.CollisionDie && s.Protected()) {
+			return nil
+		}
+
+		if collision.Action != registry.CollisionPass {
+			s.setDeath(s.collisionDeathCause(object, dot))
 			return errors.New("snake dies")
 		}
 
-		// TODO: Reload ticker.
-		//ticker = time.NewTicker(s.calculateDelay())
+		s.feed(collision.Nutrition)
 	}
 
 	s.mux.RLock()
@@ -202,10 +472,229 @@ func (s *Snake) move() error {
 	return nil
 }
 
+// drain cuts tail dot of boosted snake once per snakeBoostDrainMoves moves and returns dropped dot
+func (s *Snake) drain() (engine.Dot, bool) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if !s.unsafeBoosted() {
+		s.boostMoves = 0
+		return engine.Dot{}, false
+	}
+
+	s.boostMoves++
+	if s.boostMoves%snakeBoostDrainMoves != 0 {
+		return engine.Dot{}, false
+	}
+
+	if s.length <= snakeBoostMinLength || len(s.location) <= snakeBoostMinLength {
+		// Snake is too short to be drained: stop boost
+		s.boostedUntil = time.Time{}
+		s.boostMoves = 0
+		return engine.Dot{}, false
+	}
+
+	dot := s.location[len(s.location)-1]
+	tmpLocation := s.location[:len(s.location)-1].Copy()
+
+	if err := s.world.UpdateObject(s, s.location, tmpLocation); err != nil {
+		return engine.Dot{}, false
+	}
+
+	s.location = tmpLocation
+	s.length--
+
+	return dot, true
+}
+
+// leaveTrail creates corpse on dot dropped by boosted snake. Corpse outlives the snake, so it runs until world is
+// stopped and not until snake is stopped
+func (s *Snake) leaveTrail(dot engine.Dot) {
+	if c, err := corpse.NewCorpse(s.world, engine.Location{dot}); err == nil {
+		c.Run(s.world.Done())
+	}
+}
+
+func (s *Snake) unsafeBoosted() bool {
+	return time.Now().Before(s.boostedUntil)
+}
+
+func (s *Snake) boost() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.length <= snakeBoostMinLength {
+		return ErrTooShortToBoost
+	}
+
+	s.boostedUntil = time.Now().Add(snakeBoostDuration)
+
+	return nil
+}
+
+// Kill cuts snake on passed dot by explosion. Snake dies if the head is hit or the rest is too short. Protected
+// snake cannot be killed
+func (s *Snake) Kill(dot engine.Dot) {
+	s.killBy(dot, DeathCauseBomb, nil)
+}
+
+// KilledBy cuts snake on passed dot by explosion of bomb placed by killer. Killer snake is credited if the snake dies
+func (s *Snake) KilledBy(dot engine.Dot, killer interface{}) {
+	killerSnake, _ := killer.(*Snake)
+	if killerSnake == s {
+		// Snake blew itself up
+		killerSnake = nil
+	}
+	s.killBy(dot, DeathCauseBomb, killerSnake)
+}
+
+// Remove kills snake with passed cause regardless of protection and blocks until snake is removed from playground
+func (s *Snake) Remove(cause DeathCause) {
+	s.mux.Lock()
+	s.unsafeSetDeath(cause, nil)
+	if !s.killed {
+		close(s.chKill)
+		s.killed = true
+	}
+	done := s.chDone
+	s.mux.Unlock()
+
+	if done == nil {
+		// Snake is not running
+		s.die()
+		return
+	}
+
+	<-done
+}
+
+// KillBy cuts snake on passed dot like Kill. Snake which dies gets passed cause of death
+func (s *Snake) KillBy(dot engine.Dot, cause DeathCause) {
+	s.killBy(dot, cause, nil)
+}
+
+func (s *Snake) killBy(dot engine.Dot, cause DeathCause, killer *Snake) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.unsafeProtected() {
+		return
+	}
+
+	index := -1
+	for i, snakeDot := range s.location {
+		if snakeDot.Equals(dot) {
+			index = i
+			break
+		}
+	}
+
+	if index < 0 {
+		return
+	}
+
+	if index < snakeKillMinLength {
+		if !s.killed {
+			s.unsafeSetDeath(cause, killer)
+			close(s.chKill)
+			s.killed = true
+		}
+		return
+	}
+
+	tmpLocation := s.location[:index].Copy()
+
+	if err := s.world.UpdateObject(s, s.location, tmpLocation); err != nil {
+		return
+	}
+
+	s.location = tmpLocation
+	s.length = uint16(index)
+}
+
+// PickUp takes bombs while snake has free room for them and one flag
+func (s *Snake) PickUp(item interface{}) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	switch item := item.(type) {
+	case *bomb.Bomb:
+		if s.bombs >= snakeMaxBombs {
+			return false
+		}
+		s.bombs++
+		return true
+	case *flag.Flag:
+		if s.carriedFlag != nil {
+			return false
+		}
+		s.carriedFlag = item
+		return true
+	}
+
+	return false
+}
+
+// carryFlag moves carried flag with snake's head
+func (s *Snake) carryFlag() {
+	s.mux.RLock()
+	carriedFlag := s.carriedFlag
+	head := s.location[0]
+	team := s.team
+	s.mux.RUnlock()
+
+	if carriedFlag != nil && !carriedFlag.Carry(s, head, team) {
+		s.mux.Lock()
+		if s.carriedFlag == carriedFlag {
+			s.carriedFlag = nil
+		}
+		s.mux.Unlock()
+	}
+}
+
+func (s *Snake) armBomb() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.bombs == 0 || s.dropBomb {
+		return ErrNoBombs
+	}
+
+	s.bombs--
+	s.dropBomb = true
+
+	return nil
+}
+
+func (s *Snake) takeBombDrop() bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	drop := s.dropBomb
+	s.dropBomb = false
+	return drop
+}
+
+// placeBomb places armed bomb behind snake's tail. Fuse of the bomb burns until world is stopped even if snake is
+// stopped
+func (s *Snake) placeBomb() {
+	s.mux.RLock()
+	tail := s.location[len(s.location)-1]
+	dir := engine.CalculateDirection(s.location[len(s.location)-2], tail)
+	s.mux.RUnlock()
+
+	if dot, err := s.world.Navigate(tail, dir, 1); err == nil {
+		bomb.NewArmedBomb(s.world.Done(), s.world, dot, s)
+	}
+}
+
 func (s *Snake) calculateDelay() time.Duration {
 	s.mux.RLock()
 	defer s.mux.RUnlock()
-	return time.Duration(math.Pow(snakeSpeedFactor, float64(s.length)) * float64(snakeStartSpeed))
+	delay := time.Duration(math.Pow(snakeSpeedFactor, float64(s.length)) * float64(snakeStartSpeed))
+	if s.unsafeBoosted() {
+		return delay / snakeBoostSpeedFactor
+	}
+	return delay
 }
 
 // getNextHeadDot calculates new position of snake's head by its direction and current head position
@@ -220,34 +709,32 @@ func (s *Snake) getNextHeadDot() (engine.Dot, error) {
 	return engine.Dot{}, errors.New("cannot get next head dots: empty location")
 }
 
+// Command executes command of player. Returned error is ErrCommand
 func (s *Snake) Command(cmd Command) error {
+	if cmd == CommandBoost {
+		return s.boost()
+	}
+
+	if cmd == CommandBomb {
+		return s.armBomb()
+	}
+
 	if direction, ok := snakeCommands[cmd]; ok {
-		return fmt.Errorf("cannot execute command: %s", s.setMovementDirection(direction))
+		return s.setMovementDirection(direction)
 	}
 
-	return errors.New("cannot execute command: unknown command")
+	return ErrUnknownCommand
 }
 
+// setMovementDirection queues turn of snake. Snake turns on the next moves
 func (s *Snake) setMovementDirection(nextDir engine.Direction) error {
 	if engine.ValidDirection(nextDir) {
-		currDir := engine.CalculateDirection(s.location[1], s.location[0])
-
-		rNextDir, err := nextDir.Reverse()
-		if err != nil {
-			return fmt.Errorf("cannot set movement direction: %s", err)
-		}
-
-		// Next direction cannot be opposite to current direction
-		if rNextDir == currDir {
-			return errors.New("next direction cannot be opposite to current direction")
-		}
-
-		s.setDirection(nextDir)
-
-		return nil
+		s.mux.Lock()
+		defer s.mux.Unlock()
+		return s.unsafeQueueTurn(nextDir)
 	}
 
-	return errors.New("invalid direction")
+	return ErrInvalidDirection
 }
 
 func (s *Snake) GetLocation() engine.Location {
@@ -259,15 +746,38 @@ func (s *Snake) GetLocation() engine.Location {
 func (s *Snake) MarshalJSON() ([]byte, error) {
 	s.mux.RLock()
 	defer s.mux.RUnlock()
-	return ffjson.Marshal(&snake{
-		UUID: s.uuid,
-		Dots: s.location,
-		Type: snakeTypeLabel,
-	})
+	snakeJSON := &snake{
+		UUID:  s.uuid,
+		Dots:  s.location,
+		Type:  snakeTypeLabel,
+		Bombs: s.bombs,
+		Team:  s.team,
+
+		Protected: s.unsafeProtected(),
+	}
+	if s.carriedFlag != nil {
+		snakeJSON.Flag = s.carriedFlag.Team().Name()
+	}
+	if s.profile != nil {
+		snakeJSON.Nickname = s.profile.Nickname
+		snakeJSON.Color = s.profile.Color
+		snakeJSON.Skin = s.profile.Skin
+		snakeJSON.Bot = s.profile.Bot
+	}
+	return ffjson.Marshal(snakeJSON)
 }
 
 type snake struct {
-	UUID string       `json:"uuid"`
-	Dots []engine.Dot `json:"dots"`
-	Type string       `json:"type"`
+	UUID     string       `json:"uuid"`
+	Dots     []engine.Dot `json:"dots"`
+	Type     string       `json:"type"`
+	Nickname string       `json:"nickname,omitempty"`
+	Color    string       `json:"color,omitempty"`
+	Skin     uint8        `json:"skin"`
+	Bombs    uint8        `json:"bombs,omitempty"`
+	Team     *teams.Team  `json:"team,omitempty"`
+	Flag     string       `json:"flag,omitempty"`
+
+	Protected bool `json:"protected,omitempty"`
+	Bot       bool `json:"bot,omitempty"`
 }
@@ -3,10 +3,16 @@ package snake
 import (
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects/bomb"
+	"github.com/ivan1993spb/snake-server/objects/registry"
+	"github.com/ivan1993spb/snake-server/objects/wall"
+	"github.com/ivan1993spb/snake-server/profile"
+	"github.com/ivan1993spb/snake-server/teams"
 	"github.com/ivan1993spb/snake-server/world"
 )
 
@@ -57,13 +63,54 @@ func Test_Snake_setMovementDirection(t *testing.T) {
 	require.Nil(t, err, "cannot create object")
 
 	require.Nil(t, snake.setMovementDirection(engine.DirectionNorth))
+	require.Equal(t, engine.DirectionEast, snake.direction, "turn is queued until the next move")
+
+	require.NotNil(t, snake.setMovementDirection(engine.DirectionSouth), "opposite to queued turn")
+
+	require.Nil(t, snake.setMovementDirection(engine.DirectionWest))
+	require.Equal(t, []engine.Direction{engine.DirectionNorth, engine.DirectionWest}, snake.turns)
+
+	require.Nil(t, snake.move())
 	require.Equal(t, engine.DirectionNorth, snake.direction)
+	require.Nil(t, snake.move())
+	require.Equal(t, engine.DirectionWest, snake.direction)
+}
 
+func Test_Snake_setMovementDirection_AppliesOverflowPolicy(t *testing.T) {
+	snake := &Snake{
+		location: engine.Location{
+			{X: 10, Y: 0},
+			{X: 9, Y: 0},
+		},
+		direction: engine.DirectionEast,
+		turnQueue: TurnQueue{
+			Depth:    2,
+			Overflow: TurnOverflowDrop,
+		},
+		mux: &sync.RWMutex{},
+	}
+
+	require.Nil(t, snake.setMovementDirection(engine.DirectionNorth))
+	require.Nil(t, snake.setMovementDirection(engine.DirectionWest))
+	require.NotNil(t, snake.setMovementDirection(engine.DirectionSouth), "queue is full")
+	require.Equal(t, []engine.Direction{engine.DirectionNorth, engine.DirectionWest}, snake.turns)
+
+	snake.turnQueue.Overflow = TurnOverflowReplace
+	require.NotNil(t, snake.setMovementDirection(engine.DirectionSouth), "opposite to north before replaced turn")
+	require.Nil(t, snake.setMovementDirection(engine.DirectionEast))
+	require.Equal(t, []engine.Direction{engine.DirectionNorth, engine.DirectionEast}, snake.turns)
+
+	snake.turnQueue.Overflow = TurnOverflowShift
 	require.Nil(t, snake.setMovementDirection(engine.DirectionSouth))
-	require.Equal(t, engine.DirectionSouth, snake.direction)
+	require.Equal(t, []engine.Direction{engine.DirectionEast, engine.DirectionSouth}, snake.turns)
 
-	require.NotNil(t, snake.setMovementDirection(engine.DirectionWest))
-	require.Equal(t, engine.DirectionSouth, snake.direction)
+	snake.location = engine.Location{
+		{X: 10, Y: 0},
+		{X: 11, Y: 0},
+	}
+	snake.nextTurn()
+	require.Equal(t, engine.DirectionSouth, snake.direction, "turn to reverse direction is skipped")
+	require.Empty(t, snake.turns)
 }
 
 func Test_Snake_getNextHeadDot(t *testing.T) {
@@ -238,3 +285,536 @@ func Test_Snake_move_validLocation(t *testing.T) {
 		{11, 0},
 	}, snake.location)
 }
+
+func Test_Snake_calculateDelay_BoostDoublesSpeed(t *testing.T) {
+	snake := &Snake{
+		length: 10,
+		mux:    &sync.RWMutex{},
+	}
+	delay := snake.calculateDelay()
+
+	require.Nil(t, snake.boost())
+	require.Equal(t, delay/snakeBoostSpeedFactor, snake.calculateDelay())
+}
+
+func Test_Snake_boost_ShortSnakeCannotBoost(t *testing.T) {
+	snake := &Snake{
+		length: snakeBoostMinLength,
+		mux:    &sync.RWMutex{},
+	}
+	require.NotNil(t, snake.boost())
+	require.False(t, snake.unsafeBoosted())
+}
+
+func Test_Snake_drain_DropsTailDot(t *testing.T) {
+	world, err := world.NewWorld(100, 100)
+	require.Nil(t, err, "cannot initialize world")
+	require.NotNil(t, world, "cannot initialize world")
+
+	snake := &Snake{
+		world:  world,
+		length: 5,
+		location: engine.Location{
+			{X: 10, Y: 0},
+			{X: 9, Y: 0},
+			{X: 8, Y: 0},
+			{X: 7, Y: 0},
+			{X: 6, Y: 0},
+		},
+		direction: engine.DirectionEast,
+		mux:       &sync.RWMutex{},
+	}
+
+	err = world.CreateObject(snake, snake.location.Copy())
+	require.Nil(t, err, "cannot create object")
+
+	_, ok := snake.drain()
+	require.False(t, ok, "snake is not boosted")
+
+	require.Nil(t, snake.boost())
+
+	for i := 1; i < snakeBoostDrainMoves; i++ {
+		_, ok := snake.drain()
+		require.False(t, ok)
+	}
+
+	dot, ok := snake.drain()
+	require.True(t, ok)
+	require.Equal(t, engine.Dot{X: 6, Y: 0}, dot)
+	require.Equal(t, uint16(4), snake.length)
+	require.Equal(t, engine.Location{
+		{X: 10, Y: 0},
+		{X: 9, Y: 0},
+		{X: 8, Y: 0},
+		{X: 7, Y: 0},
+	}, snake.location)
+	require.Nil(t, world.GetObjectByDot(engine.Dot{X: 6, Y: 0}))
+}
+
+func Test_Snake_Kill_CutsSnake(t *testing.T) {
+	world, err := world.NewWorld(100, 100)
+	require.Nil(t, err, "cannot initialize world")
+
+	snake := &Snake{
+		world:  world,
+		length: 5,
+		location: engine.Location{
+			{X: 10, Y: 0},
+			{X: 9, Y: 0},
+			{X: 8, Y: 0},
+			{X: 7, Y: 0},
+			{X: 6, Y: 0},
+		},
+		direction: engine.DirectionEast,
+		chKill:    make(chan struct{}),
+		mux:       &sync.RWMutex{},
+	}
+
+	err = world.CreateObject(snake, snake.location.Copy())
+	require.Nil(t, err, "cannot create object")
+
+	snake.Kill(engine.Dot{X: 8, Y: 0})
+	require.Equal(t, uint16(2), snake.length)
+	require.Equal(t, engine.Location{
+		{X: 10, Y: 0},
+		{X: 9, Y: 0},
+	}, snake.location)
+	require.Nil(t, world.GetObjectByDot(engine.Dot{X: 8, Y: 0}))
+	require.False(t, snake.killed)
+
+	snake.Kill(engine.Dot{X: 9, Y: 0})
+	require.True(t, snake.killed)
+
+	select {
+	case <-snake.chKill:
+	default:
+		t.Fatal("kill channel is not closed")
+	}
+}
+
+func Test_Snake_PickUp_LimitsBombs(t *testing.T) {
+	snake := &Snake{
+		mux: &sync.RWMutex{},
+	}
+
+	require.False(t, snake.PickUp("item"))
+
+	for i := 0; i < snakeMaxBombs; i++ {
+		require.True(t, snake.PickUp(&bomb.Bomb{}))
+	}
+	require.False(t, snake.PickUp(&bomb.Bomb{}))
+	require.Equal(t, uint8(snakeMaxBombs), snake.bombs)
+
+	require.Nil(t, snake.Command(CommandBomb))
+	require.NotNil(t, snake.Command(CommandBomb), "bomb is already being dropped")
+	require.True(t, snake.takeBombDrop())
+	require.Equal(t, uint8(snakeMaxBombs-1), snake.bombs)
+}
+
+func Test_collideSnake_TeammatesPassWithoutFriendlyFire(t *testing.T) {
+	gameTeams, err := teams.NewTeams(2, false)
+	require.Nil(t, err)
+	red, _ := gameTeams.Get("red")
+	blue, _ := gameTeams.Get("blue")
+
+	object := &Snake{team: red, mux: &sync.RWMutex{}}
+	teammate := &Snake{team: red, mux: &sync.RWMutex{}}
+	enemy := &Snake{team: blue, mux: &sync.RWMutex{}}
+
+	collision := collideSnake(object, teammate, engine.Dot{})
+	require.Equal(t, registry.CollisionSkip, collision.Action)
+	require.Equal(t, uint16(0), object.GetKills())
+
+	collision = collideSnake(object, enemy, engine.Dot{})
+	require.Equal(t, registry.CollisionDie, collision.Action)
+	require.Equal(t, uint16(0), object.GetKills(), "kill is credited when hitter dies")
+}
+
+func Test_Snake_die_CreditsKiller(t *testing.T) {
+	w, err := world.NewWorld(100, 100)
+	require.Nil(t, err, "cannot initialize world")
+
+	gameTeams, err := teams.NewTeams(2, false)
+	require.Nil(t, err)
+	red, _ := gameTeams.Get("red")
+
+	killer := &Snake{team: red, mux: &sync.RWMutex{}}
+	snake := &Snake{
+		world:    w,
+		location: engine.Location{{X: 1, Y: 1}},
+		mux:      &sync.RWMutex{},
+	}
+	require.Nil(t, w.CreateObject(snake, snake.location.Copy()))
+
+	snake.setDeath(DeathCauseBody, killer)
+	snake.die()
+
+	require.Equal(t, uint16(1), killer.GetKills())
+	require.Equal(t, uint16(1), red.Kills())
+}
+
+func Test_Snake_move_ProtectedHitterDoesNotCreditKill(t *testing.T) {
+	w, err := world.NewWorld(100, 100)
+	require.Nil(t, err, "cannot initialize world")
+
+	other := &Snake{
+		location: engine.Location{
+			{X: 11, Y: 1},
+			{X: 11, Y: 0},
+		},
+		mux: &sync.RWMutex{},
+	}
+	require.Nil(t, w.CreateObject(other, other.location.Copy()))
+
+	snake := &Snake{
+		world:  w,
+		length: 3,
+		location: engine.Location{
+			{X: 10, Y: 0},
+			{X: 9, Y: 0},
+			{X: 8, Y: 0},
+		},
+		direction:      engine.DirectionEast,
+		protectedUntil: time.Now().Add(time.Minute),
+		mux:            &sync.RWMutex{},
+	}
+	require.Nil(t, w.CreateObject(snake, snake.location.Copy()))
+
+	require.Nil(t, snake.move())
+	require.Equal(t, uint16(0), other.GetKills())
+}
+
+func Test_Snake_move_WaitsInFrontOfTeammate(t *testing.T) {
+	world, err := world.NewWorld(100, 100)
+	require.Nil(t, err, "cannot initialize world")
+
+	gameTeams, err := teams.NewTeams(2, false)
+	require.Nil(t, err)
+	red, _ := gameTeams.Get("red")
+
+	teammate := &Snake{
+		team: red,
+		location: engine.Location{
+			{X: 11, Y: 1},
+			{X: 11, Y: 0},
+		},
+		mux: &sync.RWMutex{},
+	}
+	require.Nil(t, world.CreateObject(teammate, teammate.location.Copy()))
+
+	snake := &Snake{
+		world:  world,
+		team:   red,
+		length: 3,
+		location: engine.Location{
+			{X: 10, Y: 0},
+			{X: 9, Y: 0},
+			{X: 8, Y: 0},
+		},
+		direction: engine.DirectionEast,
+		mux:       &sync.RWMutex{},
+	}
+	require.Nil(t, world.CreateObject(snake, snake.location.Copy()))
+
+	require.Nil(t, snake.move())
+	require.Equal(t, engine.Location{
+		{X: 10, Y: 0},
+		{X: 9, Y: 0},
+		{X: 8, Y: 0},
+	}, snake.location, "head is not moved over teammate")
+
+	// Teammate has moved away
+	require.Nil(t, world.DeleteObject(teammate, teammate.location.Copy()))
+	require.Nil(t, snake.move())
+	require.Equal(t, engine.Location{
+		{X: 11, Y: 0},
+		{X: 10, Y: 0},
+		{X: 9, Y: 0},
+	}, snake.location)
+}
+
+func Test_Snake_Protect_MakesSnakeInvulnerable(t *testing.T) {
+	world, err := world.NewWorld(100, 100)
+	require.Nil(t, err, "cannot initialize world")
+
+	enemy := &Snake{
+		location: engine.Location{
+			{X: 11, Y: 1},
+			{X: 11, Y: 0},
+		},
+		chKill: make(chan struct{}),
+		mux:    &sync.RWMutex{},
+	}
+	require.Nil(t, world.CreateObject(enemy, enemy.location.Copy()))
+
+	snake := &Snake{
+		world:  world,
+		length: 3,
+		location: engine.Location{
+			{X: 10, Y: 0},
+			{X: 9, Y: 0},
+			{X: 8, Y: 0},
+		},
+		direction: engine.DirectionEast,
+		chKill:    make(chan struct{}),
+		mux:       &sync.RWMutex{},
+	}
+	require.Nil(t, world.CreateObject(snake, snake.location.Copy()))
+
+	snake.Protect(time.Minute)
+	require.True(t, snake.Protected())
+
+	collision := collideSnake(snake, enemy, engine.Dot{X: 10, Y: 0})
+	require.Equal(t, registry.CollisionSkip, collision.Action, "enemy waits in front of protected snake")
+
+	snake.Kill(engine.Dot{X: 10, Y: 0})
+	require.False(t, snake.killed)
+
+	require.Nil(t, snake.move(), "protected snake waits in front of enemy")
+	require.Equal(t, engine.Location{
+		{X: 10, Y: 0},
+		{X: 9, Y: 0},
+		{X: 8, Y: 0},
+	}, snake.location, "head is not moved over enemy")
+	require.Equal(t, snake, world.GetObjectByDot(engine.Dot{X: 10, Y: 0}))
+}
+
+func Test_NewSafeSnake_KeepsDistanceFromHeads(t *testing.T) {
+	world, err := world.NewWorld(20, 20)
+	require.Nil(t, err, "cannot initialize world")
+
+	first, err := NewSnake(world, nil, nil)
+	require.Nil(t, err)
+
+	second, err := NewSafeSnake(world, nil, nil, 5)
+	require.Nil(t, err)
+
+	head := first.GetLocation()[0]
+	for _, dot := range second.GetLocation() {
+		require.True(t, dot.DistanceTo(head) > 5)
+	}
+
+	require.False(t, farFrom(engine.Location{{X: 1, Y: 1}}, []engine.Dot{{X: 2, Y: 2}}, 2))
+	require.True(t, farFrom(engine.Location{{X: 1, Y: 1}}, []engine.Dot{{X: 2, Y: 2}}, 1))
+}
+
+func Test_Snake_Freeze_StopsMovement(t *testing.T) {
+	world, err := world.NewWorld(100, 100)
+	require.Nil(t, err, "cannot initialize world")
+
+	snake, err := NewSnake(world, profile.NewDefaultProfile(), nil)
+	require.Nil(t, err)
+
+	snake.Freeze(true)
+	require.True(t, snake.Frozen())
+
+	location := snake.GetLocation()
+
+	stop := make(chan struct{})
+	snakeStop := snake.Run(stop)
+
+	time.Sleep(snake.calculateDelay() * 3)
+	require.Equal(t, location, snake.GetLocation())
+
+	close(stop)
+	<-snakeStop
+}
+
+func Test_Snake_move_SavesDeathCauseAndKiller(t *testing.T) {
+	world, err := world.NewWorld(100, 100)
+	require.Nil(t, err, "cannot initialize world")
+
+	enemy := &Snake{
+		uuid: "enemy",
+		location: engine.Location{
+			{X: 11, Y: 1},
+			{X: 11, Y: 0},
+		},
+		profile: &profile.Profile{
+			ID:       "enemy player",
+			Nickname: "Anna",
+		},
+		chKill: make(chan struct{}),
+		mux:    &sync.RWMutex{},
+	}
+	require.Nil(t, world.CreateObject(enemy, enemy.location.Copy()))
+
+	snake := &Snake{
+		uuid:   "snake",
+		world:  world,
+		length: 3,
+		location: engine.Location{
+			{X: 10, Y: 0},
+			{X: 9, Y: 0},
+			{X: 8, Y: 0},
+		},
+		direction: engine.DirectionEast,
+		chKill:    make(chan struct{}),
+		mux:       &sync.RWMutex{},
+	}
+	require.Nil(t, world.CreateObject(snake, snake.location.Copy()))
+
+	require.NotNil(t, snake.move())
+
+	death := snake.Death()
+	require.Equal(t, DeathCauseBody, death.Cause)
+	require.Equal(t, "snake", death.Snake)
+	require.Equal(t, "enemy", death.Killer)
+	require.Equal(t, "enemy player", death.KillerPlayer)
+	require.Equal(t, "Anna", death.KillerNickname)
+	require.Equal(t, uint16(3), death.Length)
+
+	snake.setDeath(DeathCauseDisconnect, nil)
+	require.Equal(t, DeathCauseBody, snake.Death().Cause, "the first cause is kept")
+}
+
+func Test_Snake_KillBy_SavesDeathCause(t *testing.T) {
+	world, err := world.NewWorld(100, 100)
+	require.Nil(t, err, "cannot initialize world")
+
+	snake, err := NewSnake(world, profile.NewDefaultProfile(), nil)
+	require.Nil(t, err)
+
+	snake.Kill(snake.GetLocation()[0])
+	require.Equal(t, DeathCauseBomb, snake.Death().Cause)
+
+	snake, err = NewSnake(world, profile.NewDefaultProfile(), nil)
+	require.Nil(t, err)
+
+	stop := make(chan struct{})
+	snakeStop := snake.Run(stop)
+	close(stop)
+	<-snakeStop
+
+	require.Equal(t, DeathCauseDisconnect, snake.Death().Cause)
+	require.Nil(t, world.GetObjectByDot(snake.GetLocation()[0]))
+}
+
+func Test_Snake_Command_ReturnsErrorCodes(t *testing.T) {
+	snake := &Snake{
+		length: 3,
+		location: engine.Location{
+			{X: 10, Y: 0},
+			{X: 9, Y: 0},
+			{X: 8, Y: 0},
+		},
+		direction: engine.DirectionEast,
+		mux:       &sync.RWMutex{},
+	}
+
+	require.Nil(t, snake.Command(CommandToNorth), "successful command returns no error")
+	require.Equal(t, ErrReverseDirection, snake.Command(CommandToSouth))
+	require.Equal(t, ErrUnknownCommand, snake.Command(Command("jump")))
+	require.Equal(t, ErrNoBombs, snake.Command(CommandBomb))
+	require.Equal(t, ErrTooShortToBoost, snake.Command(CommandBoost))
+	require.Equal(t, "reverse_direction", ErrReverseDirection.Code())
+}
+
+func Test_Snake_Remove_RemovesSnakeWhichIsNotRunning(t *testing.T) {
+	w, err := world.NewWorld(100, 100)
+	require.Nil(t, err, "cannot initialize world")
+
+	snake, err := NewSnake(w, &profile.Profile{}, nil)
+	require.Nil(t, err)
+	snake.Protect(time.Minute)
+
+	snake.Remove(DeathCauseReset)
+
+	require.Equal(t, DeathCauseReset, snake.Death().Cause)
+	require.False(t, w.ObjectExists(snake))
+}
+
+func Test_Snake_KilledBy_CreditsOwnerOfBomb(t *testing.T) {
+	world, err := world.NewWorld(100, 100)
+	require.Nil(t, err, "cannot initialize world")
+
+	owner, err := NewSnake(world, profile.NewDefaultProfile(), nil)
+	require.Nil(t, err)
+
+	snake, err := NewSnake(world, profile.NewDefaultProfile(), nil)
+	require.Nil(t, err)
+
+	snake.KilledBy(snake.GetLocation()[0], owner)
+	require.Equal(t, DeathCauseBomb, snake.Death().Cause)
+	require.Equal(t, owner.GetUUID(), snake.Death().Killer)
+
+	snake.die()
+	require.Equal(t, uint16(1), owner.GetKills())
+
+	owner.KilledBy(owner.GetLocation()[0], owner)
+	require.Equal(t, DeathCauseBomb, owner.Death().Cause)
+	require.Empty(t, owner.Death().Killer, "snake which blew itself up has no killer")
+}
+
+func Test_Snake_move_ArmedBombKillsByOwner(t *testing.T) {
+	world, err := world.NewWorld(100, 100)
+	require.Nil(t, err, "cannot initialize world")
+
+	owner := &Snake{
+		uuid: "owner",
+		mux:  &sync.RWMutex{},
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	_, err = bomb.NewArmedBomb(stop, world, engine.Dot{X: 11, Y: 0}, owner)
+	require.Nil(t, err)
+
+	snake := &Snake{
+		uuid:   "snake",
+		world:  world,
+		length: 3,
+		location: engine.Location{
+			{X: 10, Y: 0},
+			{X: 9, Y: 0},
+			{X: 8, Y: 0},
+		},
+		direction: engine.DirectionEast,
+		chKill:    make(chan struct{}),
+		mux:       &sync.RWMutex{},
+	}
+	require.Nil(t, world.CreateObject(snake, snake.location.Copy()))
+
+	require.NotNil(t, snake.move())
+
+	death := snake.Death()
+	require.Equal(t, DeathCauseBomb, death.Cause)
+	require.Equal(t, "owner", death.Killer)
+}
+
+func Test_Snake_collisionDeathCause_DependsOnObject(t *testing.T) {
+	owner := &Snake{
+		mux: &sync.RWMutex{},
+	}
+	snake := &Snake{
+		mux: &sync.RWMutex{},
+	}
+
+	stop := make(chan struct{})
+	close(stop)
+
+	world, err := world.NewWorld(100, 100)
+	require.Nil(t, err, "cannot initialize world")
+
+	ownBomb, err := bomb.NewArmedBomb(stop, world, engine.Dot{X: 1, Y: 1}, snake)
+	require.Nil(t, err)
+	enemyBomb, err := bomb.NewArmedBomb(stop, world, engine.Dot{X: 2, Y: 2}, owner)
+	require.Nil(t, err)
+
+	cause, killer := snake.collisionDeathCause(&wall.Wall{}, engine.Dot{})
+	require.Equal(t, DeathCauseWall, cause)
+	require.Nil(t, killer)
+
+	cause, killer = snake.collisionDeathCause(enemyBomb, engine.Dot{X: 2, Y: 2})
+	require.Equal(t, DeathCauseBomb, cause)
+	require.Equal(t, owner, killer)
+
+	cause, killer = snake.collisionDeathCause(ownBomb, engine.Dot{X: 1, Y: 1})
+	require.Equal(t, DeathCauseBomb, cause)
+	require.Nil(t, killer, "snake does not kill itself")
+
+	cause, killer = snake.collisionDeathCause(new(int), engine.Dot{})
+	require.Equal(t, DeathCauseUnknown, cause)
+	require.Nil(t, killer)
+}
@@ -0,0 +1,164 @@
+package snake
+
+import (
+	"github.com/ivan1993spb/snake-server/engine"
+)
+
+// Maximal depth of turn queue
+const maxTurnQueueDepth = 8
+
+// Default depth of turn queue
+const defaultTurnQueueDepth = 3
+
+// TurnOverflow decides what happens with turn which does not fit in full queue
+type TurnOverflow uint8
+
+const (
+	// TurnOverflowDrop drops new turn
+	TurnOverflowDrop TurnOverflow = iota
+	// TurnOverflowReplace replaces the last queued turn by new turn
+	TurnOverflowReplace
+	// TurnOverflowShift drops the oldest queued turn
+	TurnOverflowShift
+)
+
+var turnOverflowLabels = map[TurnOverflow]string{
+	TurnOverflowDrop:    "drop",
+	TurnOverflowReplace: "replace",
+	TurnOverflowShift:   "shift",
+}
+
+func (o TurnOverflow) String() string {
+	if label, ok := turnOverflowLabels[o]; ok {
+		return label
+	}
+	return "unknown"
+}
+
+type ErrInvalidTurnQueue string
+
+func (e ErrInvalidTurnQueue) Error() string {
+	return "invalid turn queue: " + string(e)
+}
+
+// ParseTurnOverflow returns overflow policy by label
+func ParseTurnOverflow(label string) (TurnOverflow, error) {
+	for overflow, overflowLabel := range turnOverflowLabels {
+		if overflowLabel == label {
+			return overflow, nil
+		}
+	}
+	return 0, ErrInvalidTurnQueue("unknown overflow policy")
+}
+
+// TurnQueue contains settings of queue of turns of snake. Snake consumes one queued turn per move
+type TurnQueue struct {
+	// Depth is count of turns which can be queued. Zero means default depth
+	Depth    int
+	Overflow TurnOverflow
+}
+
+// DefaultTurnQueue returns settings of queue which drops turns sent when queue is full
+func DefaultTurnQueue() TurnQueue {
+	return TurnQueue{
+		Depth:    defaultTurnQueueDepth,
+		Overflow: TurnOverflowDrop,
+	}
+}
+
+// Validate checks settings of turn queue
+func (q TurnQueue) Validate() error {
+	if q.Depth < 0 || q.Depth > maxTurnQueueDepth {
+		return ErrInvalidTurnQueue("depth out of range")
+	}
+	if _, ok := turnOverflowLabels[q.Overflow]; !ok {
+		return ErrInvalidTurnQueue("unknown overflow policy")
+	}
+	return nil
+}
+
+func (q TurnQueue) depth() int {
+	if q.Depth == 0 {
+		return defaultTurnQueueDepth
+	}
+	return q.Depth
+}
+
+// SetTurnQueue sets up queue of turns of snake. Zero depth means default depth
+func (s *Snake) SetTurnQueue(q TurnQueue) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.turnQueue = q
+	if len(s.turns) > q.depth() {
+		s.turns = s.turns[:q.depth()]
+	}
+}
+
+// unsafeQueueTurn validates turn against the last queued direction or against current movement direction and puts
+// the turn in the queue. Full queue is handled by overflow policy
+func (s *Snake) unsafeQueueTurn(nextDir engine.Direction) error {
+	turns := s.turns
+
+	if len(turns) >= s.turnQueue.depth() {
+		switch s.turnQueue.Overflow {
+		case TurnOverflowReplace:
+			turns = turns[:len(turns)-1]
+		case TurnOverflowShift:
+			turns = turns[1:]
+		default:
+			return ErrTurnQueueFull
+		}
+	}
+
+	lastDir := s.unsafeMovementDirection()
+	if len(turns) > 0 {
+		lastDir = turns[len(turns)-1]
+	}
+
+	rNextDir, err := nextDir.Reverse()
+	if err != nil {
+		return ErrInvalidDirection
+	}
+
+	// Next direction cannot be opposite to previous direction
+	if rNextDir == lastDir {
+		return ErrReverseDirection
+	}
+
+	// The same direction changes nothing
+	if nextDir == lastDir {
+		return nil
+	}
+
+	s.turns = append(turns, nextDir)
+
+	return nil
+}
+
+// unsafeMovementDirection returns direction of the last move of snake
+func (s *Snake) unsafeMovementDirection() engine.Direction {
+	if len(s.location) > 1 {
+		return engine.CalculateDirection(s.location[1], s.location[0])
+	}
+	return s.direction
+}
+
+// nextTurn consumes one queued turn and sets it as direction of snake. Turns which would reverse snake into itself
+// are skipped
+func (s *Snake) nextTurn() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	currDir := s.unsafeMovementDirection()
+
+	for len(s.turns) > 0 {
+		turn := s.turns[0]
+		s.turns = s.turns[1:]
+
+		if rTurn, err := turn.Reverse(); err == nil && rTurn != currDir {
+			s.direction = turn
+			return
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package wall
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/ivan1993spb/snake-server/objects/registry"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+const wallPerNDots = 100
+
+type Observer struct{}
+
+func NewObserver(options registry.Options) (registry.Observer, error) {
+	return Observer{}, nil
+}
+
+func (Observer) Observe(stop <-chan struct{}, w *world.World, logger logrus.FieldLogger) {
+	go func() {
+		for i := uint16(0); i < w.Size()/wallPerNDots; i++ {
+			if _, err := NewRandWall(w); err != nil {
+				logger.WithError(err).Error("cannot create rand wall")
+			}
+		}
+	}()
+}
@@ -0,0 +1,47 @@
+package wall
+
+import (
+	"sync"
+
+	"github.com/pquerna/ffjson/ffjson"
+	"github.com/satori/go.uuid"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/objects/registry"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+func init() {
+	registry.Register(registry.Type{
+		Label:  wallTypeLabel,
+		Object: &Wall{},
+		Snapshot: func(object interface{}) ([]byte, error) {
+			w := object.(*Wall)
+			w.mux.RLock()
+			defer w.mux.RUnlock()
+			return ffjson.Marshal(w.location)
+		},
+		Restore:     restoreWall,
+		NewObserver: NewObserver,
+	})
+}
+
+func restoreWall(stop <-chan struct{}, world *world.World, data []byte) (interface{}, error) {
+	var location engine.Location
+	if err := ffjson.Unmarshal(data, &location); err != nil {
+		return nil, ErrCreateWall(err.Error())
+	}
+
+	wall := &Wall{
+		uuid:     uuid.Must(uuid.NewV4()).String(),
+		world:    world,
+		location: location,
+		mux:      &sync.RWMutex{},
+	}
+
+	if err := world.CreateObject(wall, location); err != nil {
+		return nil, ErrCreateWall(err.Error())
+	}
+
+	return wall, nil
+}
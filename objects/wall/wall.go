@@ -47,6 +47,26 @@ func NewWall(world *world.World, dm *engine.DotsMask) (*Wall, error) {
 	return wall, nil
 }
 
+// NewWallLocation creates wall on available dots of passed location
+func NewWallLocation(world *world.World, location engine.Location) (*Wall, error) {
+	wall := &Wall{
+		uuid:  uuid.Must(uuid.NewV4()).String(),
+		world: world,
+		mux:   &sync.RWMutex{},
+	}
+
+	location, err := world.CreateObjectAvailableDots(wall, location)
+	if err != nil {
+		return nil, ErrCreateWall(err.Error())
+	}
+
+	wall.mux.Lock()
+	wall.location = location
+	wall.mux.Unlock()
+
+	return wall, nil
+}
+
 func NewLongWall(world *world.World) (*Wall, error) {
 	wall := &Wall{
 		uuid:  uuid.Must(uuid.NewV4()).String(),
@@ -85,7 +105,7 @@ func (w *Wall) Break(dot engine.Dot) {
 	if w.location.Contains(dot) {
 		location := w.location.Delete(dot)
 
-		if w.location.DotCount() > 0 {
+		if location.DotCount() > 0 {
 			if err := w.world.UpdateObject(w, w.location, location); err != nil {
 				// TODO: Handle error.
 			} else {
@@ -93,10 +113,24 @@ func (w *Wall) Break(dot engine.Dot) {
 			}
 			return
 		}
+
+		if err := w.world.DeleteObject(w, w.location); err == nil {
+			w.location = location
+		}
+	}
+}
+
+// Destroy removes wall from playground
+func (w *Wall) Destroy() {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	if w.location.Empty() {
+		return
 	}
 
-	if w.location.DotCount() == 0 {
-		w.world.DeleteObject(w, w.location)
+	if err := w.world.DeleteObject(w, w.location); err == nil {
+		w.location = nil
 	}
 }
 
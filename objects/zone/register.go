@@ -0,0 +1,10 @@
+package zone
+
+import "github.com/ivan1993spb/snake-server/objects/registry"
+
+func init() {
+	registry.Register(registry.Type{
+		Label:  zoneTypeLabel,
+		Object: &Zone{},
+	})
+}
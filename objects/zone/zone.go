@@ -0,0 +1,129 @@
+package zone
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pquerna/ffjson/ffjson"
+	"github.com/satori/go.uuid"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+const zoneTypeLabel = "zone"
+
+// Zone is non-blocking square region of playground which snakes fight for
+type Zone struct {
+	uuid     string
+	world    *world.World
+	size     uint8
+	location engine.Location
+	owner    string
+	mux      *sync.RWMutex
+}
+
+type ErrCreateZone string
+
+func (e ErrCreateZone) Error() string {
+	return "cannot create zone: " + string(e)
+}
+
+// NewZone creates square zone with passed side size on random place
+func NewZone(world *world.World, size uint8) (*Zone, error) {
+	rect, err := world.RandomRect(size, size)
+	if err != nil {
+		return nil, ErrCreateZone(err.Error())
+	}
+
+	zone := &Zone{
+		uuid:     uuid.Must(uuid.NewV4()).String(),
+		world:    world,
+		size:     size,
+		location: rect.Location(),
+		mux:      &sync.RWMutex{},
+	}
+
+	if err := world.CreateRegion(zone, zone.location); err != nil {
+		return nil, ErrCreateZone(err.Error())
+	}
+
+	return zone, nil
+}
+
+func (z *Zone) String() string {
+	z.mux.RLock()
+	defer z.mux.RUnlock()
+	return fmt.Sprintf("zone %s", z.location)
+}
+
+func (z *Zone) GetUUID() string {
+	return z.uuid
+}
+
+// Contains returns true if dot is inside the zone
+func (z *Zone) Contains(dot engine.Dot) bool {
+	z.mux.RLock()
+	defer z.mux.RUnlock()
+	return z.location.Contains(dot)
+}
+
+// Relocate moves zone to random place and resets owner
+func (z *Zone) Relocate() error {
+	rect, err := z.world.RandomRect(z.size, z.size)
+	if err != nil {
+		return err
+	}
+
+	location := rect.Location()
+
+	z.mux.Lock()
+	z.location = location
+	z.owner = ""
+	z.mux.Unlock()
+
+	return z.world.UpdateRegion(z, location)
+}
+
+// Owner returns uuid of snake which holds the zone
+func (z *Zone) Owner() string {
+	z.mux.RLock()
+	defer z.mux.RUnlock()
+	return z.owner
+}
+
+// SetOwner sets uuid of snake which holds the zone. Empty owner means that zone is free or contested
+func (z *Zone) SetOwner(owner string) {
+	z.mux.Lock()
+	if z.owner == owner {
+		z.mux.Unlock()
+		return
+	}
+	z.owner = owner
+	location := z.location
+	z.mux.Unlock()
+
+	z.world.UpdateRegion(z, location)
+}
+
+func (z *Zone) Delete() {
+	z.world.DeleteRegion(z)
+}
+
+func (z *Zone) MarshalJSON() ([]byte, error) {
+	z.mux.RLock()
+	defer z.mux.RUnlock()
+	return ffjson.Marshal(&zone{
+		UUID:  z.uuid,
+		Dots:  z.location,
+		Type:  zoneTypeLabel,
+		Owner: z.owner,
+	})
+}
+
+type zone struct {
+	UUID  string       `json:"uuid"`
+	Dots  []engine.Dot `json:"dots"`
+	Type  string       `json:"type"`
+	Owner string       `json:"owner,omitempty"`
+}
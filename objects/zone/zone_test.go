@@ -0,0 +1,42 @@
+package zone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/engine"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+func Test_NewZone_DoesNotBlockObjects(t *testing.T) {
+	w, err := world.NewWorld(20, 20)
+	require.Nil(t, err)
+
+	z, err := NewZone(w, 20)
+	require.Nil(t, err)
+
+	dot := engine.Dot{X: 3, Y: 4}
+	require.True(t, z.Contains(dot))
+	require.Equal(t, []interface{}{z}, w.GetRegionsByDot(dot))
+	require.Nil(t, w.GetObjectByDot(dot))
+	require.Nil(t, w.CreateObject(&struct{}{}, engine.Location{dot}))
+}
+
+func Test_Zone_RelocateResetsOwner(t *testing.T) {
+	w, err := world.NewWorld(20, 20)
+	require.Nil(t, err)
+
+	z, err := NewZone(w, 5)
+	require.Nil(t, err)
+
+	z.SetOwner("owner")
+	require.Equal(t, "owner", z.Owner())
+
+	require.Nil(t, z.Relocate())
+	require.Empty(t, z.Owner())
+	require.Len(t, w.GetRegions(), 1)
+
+	z.Delete()
+	require.Empty(t, w.GetRegions())
+}
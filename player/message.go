@@ -9,15 +9,21 @@ const (
 	MessageTypeError
 	MessageTypeCountdown
 	MessageTypeObjects
+	MessageTypeLives
+	MessageTypeSpectator
+	MessageTypeCommandError
 )
 
 var messageTypeJSONs = map[MessageType][]byte{
-	MessageTypeSize:      []byte(`"size"`),
-	MessageTypeSnake:     []byte(`"snake"`),
-	MessageTypeNotice:    []byte(`"notice"`),
-	MessageTypeError:     []byte(`"error"`),
-	MessageTypeCountdown: []byte(`"countdown"`),
-	MessageTypeObjects:   []byte(`"objects"`),
+	MessageTypeSize:         []byte(`"size"`),
+	MessageTypeSnake:        []byte(`"snake"`),
+	MessageTypeNotice:       []byte(`"notice"`),
+	MessageTypeError:        []byte(`"error"`),
+	MessageTypeCountdown:    []byte(`"countdown"`),
+	MessageTypeObjects:      []byte(`"objects"`),
+	MessageTypeLives:        []byte(`"lives"`),
+	MessageTypeSpectator:    []byte(`"spectator"`),
+	MessageTypeCommandError: []byte(`"command_error"`),
 }
 
 func (t MessageType) MarshalJSON() ([]byte, error) {
@@ -28,12 +34,15 @@ func (t MessageType) MarshalJSON() ([]byte, error) {
 }
 
 var messageTypeLabels = map[MessageType]string{
-	MessageTypeSize:      "size",
-	MessageTypeSnake:     "snake",
-	MessageTypeNotice:    "notice",
-	MessageTypeError:     "error",
-	MessageTypeCountdown: "countdown",
-	MessageTypeObjects:   "objects",
+	MessageTypeSize:         "size",
+	MessageTypeSnake:        "snake",
+	MessageTypeNotice:       "notice",
+	MessageTypeError:        "error",
+	MessageTypeCountdown:    "countdown",
+	MessageTypeObjects:      "objects",
+	MessageTypeLives:        "lives",
+	MessageTypeSpectator:    "spectator",
+	MessageTypeCommandError: "command_error",
 }
 
 func (t MessageType) String() string {
@@ -107,3 +116,41 @@ func NewMessageObjects(objects []interface{}) Message {
 		Payload: MessageObjects(objects),
 	}
 }
+
+// MessageLives is count of snakes which player can get yet
+type MessageLives uint
+
+func NewMessageLives(lives uint) Message {
+	return Message{
+		Type:    MessageTypeLives,
+		Payload: MessageLives(lives),
+	}
+}
+
+// MessageSpectator contains reason why player became spectator
+type MessageSpectator string
+
+func NewMessageSpectator(reason string) Message {
+	return Message{
+		Type:    MessageTypeSpectator,
+		Payload: MessageSpectator(reason),
+	}
+}
+
+// MessageCommandError describes rejected command of player
+type MessageCommandError struct {
+	Command string `json:"command"`
+	Code    string `json:"code"`
+	Text    string `json:"text"`
+}
+
+func NewMessageCommandError(command, code, text string) Message {
+	return Message{
+		Type: MessageTypeCommandError,
+		Payload: MessageCommandError{
+			Command: command,
+			Code:    code,
+			Text:    text,
+		},
+	}
+}
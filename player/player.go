@@ -1,29 +1,88 @@
 package player
 
 import (
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/profile"
+	"github.com/ivan1993spb/snake-server/teams"
 	"github.com/ivan1993spb/snake-server/world"
 )
 
-const countdown = 5
-
 const chanMessageBuffer = 16
 
-const chanErrorBuffer = 32
+// Interval of checking whether player can get new snake
+const spawnCheckInterval = time.Millisecond * 250
+
+// SpawnPolicy decides whether player can get new snake
+type SpawnPolicy interface {
+	AllowSpawn() bool
+}
+
+// Player gets not more than commandErrorsLimit command errors per commandErrorsInterval. Other errors are dropped
+const (
+	commandErrorsLimit    = 5
+	commandErrorsInterval = time.Second
+)
+
+// CommandRespawn is sent by dead player to get new snake if respawn is manual
+const CommandRespawn = "respawn"
 
 type Player struct {
-	world  *world.World
-	logger logrus.FieldLogger
+	world   *world.World
+	profile *profile.Profile
+	team    *teams.Team
+	policy  SpawnPolicy
+	respawn Respawn
+	// parking keeps snake of player after disconnection. Parking may be nil
+	parking *Parking
+	logger  logrus.FieldLogger
+
+	// errors limits command errors of all snakes of player: a new life does not reset the limit
+	errors *errorsLimiter
+
+	// leave is closed when player leaves the game and becomes spectator
+	leave       chan struct{}
+	leaveOnce   *sync.Once
+	leaveReason string
 }
 
-func NewPlayer(logger logrus.FieldLogger, world *world.World) *Player {
+func NewPlayer(logger logrus.FieldLogger, world *world.World, profile *profile.Profile, team *teams.Team,
+	policy SpawnPolicy, respawn Respawn, parking *Parking) *Player {
 	return &Player{
-		logger: logger,
-		world:  world,
+		logger:  logger,
+		world:   world,
+		profile: profile,
+		team:    team,
+		policy:  policy,
+		respawn: respawn,
+		parking: parking,
+
+		errors: newErrorsLimiter(commandErrorsLimit, commandErrorsInterval),
+
+		leave:     make(chan struct{}),
+		leaveOnce: &sync.Once{},
+	}
+}
+
+// Leave kills snake of player and makes player spectator with passed reason. Snake of player who left is not parked
+func (p *Player) Leave(reason string) {
+	p.leaveOnce.Do(func() {
+		p.leaveReason = reason
+		close(p.leave)
+	})
+}
+
+// left returns true if player has left the game
+func (p *Player) left() bool {
+	select {
+	case <-p.leave:
+		return true
+	default:
+		return false
 	}
 }
 
@@ -32,7 +91,10 @@ func (p *Player) Start(stop <-chan struct{}, chin <-chan string) <-chan Message
 	localStopper := make(chan struct{})
 
 	go func() {
-		<-stop
+		select {
+		case <-stop:
+		case <-p.leave:
+		}
 		close(localStopper)
 	}()
 
@@ -41,63 +103,248 @@ func (p *Player) Start(stop <-chan struct{}, chin <-chan string) <-chan Message
 
 		chout <- NewMessageNotice("welcome to snake server!")
 		chout <- NewMessageSize(p.world.Width(), p.world.Height())
-		chout <- NewMessageObjects(p.world.GetObjects())
+		chout <- NewMessageObjects(append(p.world.GetObjects(), p.world.GetRegions()...))
+		if p.team != nil {
+			chout <- NewMessageNotice("your team is " + p.team.Name())
+		}
 
-		for {
-			chout <- NewMessageCountdown(countdown)
+		p.play(localStopper, chin, chout)
 
-			timer := time.NewTimer(time.Second * countdown)
-			select {
-			case <-timer.C:
-				timer.Stop()
-			case <-localStopper:
-				timer.Stop()
-				return
-			}
+		// Player who left keeps watching the game until external stop
+		if p.left() {
+			chout <- NewMessageSpectator(p.leaveReason)
+			<-stop
+		}
+	}()
+
+	return chout
+}
 
-			chout <- NewMessageNotice("start")
+// play gives snakes to player until player is stopped or has no lives left
+func (p *Player) play(stop <-chan struct{}, chin <-chan string, chout chan<- Message) {
+	lives := p.respawn.Lives
+	resumed := false
 
-			s, err := snake.NewSnake(p.world)
-			if err != nil {
-				chout <- NewMessageError("cannot create snake")
-				p.logger.Errorln("cannot create snake to player:", err)
-				continue
-			}
-			snakeStop := s.Run(localStopper)
+	// Reconnected player resumes control of parked snake
+	if r, ok := p.parking.take(p.profile.ID); ok {
+		lives = r.lives
+		resumed = true
 
-			chout <- NewMessageSnake(s.GetUUID())
+		chout <- NewMessageNotice("resume")
+		chout <- NewMessageSnake(r.snake.GetUUID())
+		if p.respawn.Lives > 0 {
+			chout <- NewMessageLives(uint(lives))
+		}
 
-			p.processSnakeCommands(snakeStop, chin, s)
+		if !p.control(stop, chin, chout, r) {
+			return
+		}
 
-			select {
-			case <-snakeStop:
-			case <-localStopper:
-				return
+		if p.respawn.Lives > 0 && lives == 0 {
+			chout <- NewMessageSpectator("no lives left")
+			<-stop
+			return
+		}
+	}
+
+	for respawn := resumed; ; respawn = true {
+		if respawn && p.respawn.Manual && !p.waitRespawnCommand(stop, chin, chout) {
+			return
+		}
+
+		allowed, waited := p.waitSpawn(stop, chout)
+		if !allowed {
+			return
+		}
+
+		// Player who waited for the round gets snake at once when the round starts
+		if !waited && !p.waitDelay(stop, chout) {
+			return
+		}
+
+		chout <- NewMessageNotice("start")
+
+		s, err := p.respawn.Spawn(p.world, p.profile, p.team)
+		if err != nil {
+			chout <- NewMessageError("cannot create snake")
+			p.logger.Errorln("cannot create snake to player:", err)
+			continue
+		}
+		if p.respawn.Lives > 0 {
+			lives--
+		}
+
+		r := runSnake(s, lives)
+
+		chout <- NewMessageSnake(s.GetUUID())
+
+		if p.respawn.Lives > 0 {
+			chout <- NewMessageLives(uint(lives))
+		}
+
+		if !p.control(stop, chin, chout, r) {
+			return
+		}
+
+		if p.respawn.Lives > 0 && lives == 0 {
+			chout <- NewMessageSpectator("no lives left")
+			<-stop
+			return
+		}
+	}
+}
+
+// control passes commands of player to running snake until the snake dies. It returns false if player is stopped.
+// Snake of stopped player is parked for reconnection grace period. Snake of player who left is killed
+func (p *Player) control(stop <-chan struct{}, chin <-chan string, chout chan<- Message, r *running) bool {
+	done := p.processSnakeCommands(stop, r.done, chin, chout, r.snake)
+
+	select {
+	case <-r.done:
+		<-done
+		return true
+	case <-stop:
+		<-done
+		if p.left() {
+			r.stop()
+		} else {
+			p.parking.park(p.profile.ID, r)
+		}
+		return false
+	}
+}
+
+// waitDelay sends countdown and blocks for respawn delay. It returns false if player is stopped
+func (p *Player) waitDelay(stop <-chan struct{}, chout chan<- Message) bool {
+	if p.respawn.Delay <= 0 {
+		return true
+	}
+
+	chout <- NewMessageCountdown(p.respawn.countdown())
+
+	timer := time.NewTimer(p.respawn.Delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-stop:
+		return false
+	}
+}
+
+// waitRespawnCommand blocks until player sends respawn command. It returns false if player is stopped
+func (p *Player) waitRespawnCommand(stop <-chan struct{}, chin <-chan string, chout chan<- Message) bool {
+	chout <- NewMessageNotice("send respawn command to get new snake")
+
+	for {
+		select {
+		case command, ok := <-chin:
+			if !ok {
+				return false
+			}
+			if command == CommandRespawn {
+				return true
 			}
+		case <-stop:
+			return false
 		}
-	}()
+	}
+}
 
-	return chout
+// waitSpawn blocks until spawn policy allows new snake. It returns false if player is stopped. Second returned value
+// is true if player had to wait
+func (p *Player) waitSpawn(stop <-chan struct{}, chout chan<- Message) (bool, bool) {
+	if p.policy.AllowSpawn() {
+		return true, false
+	}
+
+	chout <- NewMessageNotice("wait for the next round")
+
+	ticker := time.NewTicker(spawnCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if p.policy.AllowSpawn() {
+				return true, true
+			}
+		case <-stop:
+			return false, true
+		}
+	}
 }
 
-func (p *Player) processSnakeCommands(stop <-chan struct{}, chin <-chan string, s *snake.Snake) <-chan error {
-	errch := make(chan error, chanErrorBuffer)
+// processSnakeCommands passes commands to snake and reports rejected commands to player. Returned channel is closed
+// when processing is finished
+func (p *Player) processSnakeCommands(stop, snakeStop <-chan struct{}, chin <-chan string, chout chan<- Message,
+	s *snake.Snake) <-chan struct{} {
+	done := make(chan struct{})
 
 	go func() {
-		defer close(errch)
+		defer close(done)
+
 		for {
 			select {
 			case <-stop:
 				return
+			case <-snakeStop:
+				return
 			case command := <-chin:
 				p.logger.WithField("command", command).Debug("received snake command")
-				if err := s.Command(snake.Command(command)); err != nil {
-					// TODO: Handle error and send it to channel with timeout!
-					//errch <- err
+
+				// Living snake does not need respawn
+				if command == CommandRespawn {
+					continue
+				}
+
+				err := s.Command(snake.Command(command))
+				if err == nil || !p.errors.allow(time.Now()) {
+					continue
+				}
+
+				code := snake.ErrUnknownCommand.Code()
+				if errCommand, ok := err.(snake.ErrCommand); ok {
+					code = errCommand.Code()
+				}
+
+				select {
+				case chout <- NewMessageCommandError(command, code, err.Error()):
+				default:
+					// Errors are not important enough to block commands
 				}
 			}
 		}
 	}()
 
-	return errch
+	return done
+}
+
+// errorsLimiter lets through not more than limit errors per interval
+type errorsLimiter struct {
+	limit    int
+	interval time.Duration
+	count    int
+	since    time.Time
+}
+
+func newErrorsLimiter(limit int, interval time.Duration) *errorsLimiter {
+	return &errorsLimiter{
+		limit:    limit,
+		interval: interval,
+	}
+}
+
+// allow returns true if error can be reported at passed moment
+func (l *errorsLimiter) allow(now time.Time) bool {
+	if now.Sub(l.since) >= l.interval {
+		l.since = now
+		l.count = 0
+	}
+	if l.count >= l.limit {
+		return false
+	}
+	l.count++
+	return true
 }
@@ -0,0 +1,102 @@
+package player
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/profile"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+func Test_errorsLimiter_allow_LimitsErrorsPerInterval(t *testing.T) {
+	limiter := newErrorsLimiter(2, time.Second)
+	now := time.Now()
+
+	require.True(t, limiter.allow(now))
+	require.True(t, limiter.allow(now.Add(time.Millisecond*100)))
+	require.False(t, limiter.allow(now.Add(time.Millisecond*200)))
+	require.False(t, limiter.allow(now.Add(time.Millisecond*900)))
+
+	require.True(t, limiter.allow(now.Add(time.Second)), "next interval")
+	require.True(t, limiter.allow(now.Add(time.Second+time.Millisecond)))
+	require.False(t, limiter.allow(now.Add(time.Second+time.Millisecond*2)))
+}
+
+type allowSpawnPolicy struct{}
+
+func (allowSpawnPolicy) AllowSpawn() bool {
+	return true
+}
+
+func Test_Player_Leave_KillsSnakeAndMakesSpectator(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	w, err := world.NewWorld(20, 20)
+	require.Nil(t, err)
+
+	pr := profile.NewDefaultProfile()
+	pr.ID = "player"
+
+	parking := NewParking(DefaultReconnect())
+	p := NewPlayer(logger, w, pr, nil, allowSpawnPolicy{}, Respawn{}, parking)
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	chout := p.Start(stop, make(chan string))
+
+	waitMessage := func(messageType MessageType) Message {
+		timeout := time.After(time.Second)
+		for {
+			select {
+			case message := <-chout:
+				if message.Type == messageType {
+					return message
+				}
+			case <-timeout:
+				t.Fatalf("no message of type %s", messageType)
+			}
+		}
+	}
+
+	waitMessage(MessageTypeSnake)
+
+	p.Leave("idle")
+	p.Leave("twice")
+
+	message := waitMessage(MessageTypeSpectator)
+	require.Equal(t, MessageSpectator("idle"), message.Payload)
+	require.Equal(t, 0, parking.Count(), "snake of player who left is not parked")
+}
+
+func Test_Player_processSnakeCommands_SharesErrorsLimitBetweenSnakes(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	w, err := world.NewWorld(20, 20)
+	require.Nil(t, err)
+
+	p := NewPlayer(logger, w, profile.NewDefaultProfile(), nil, allowSpawnPolicy{}, Respawn{}, nil)
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	chin := make(chan string)
+	chout := make(chan Message, commandErrorsLimit*2)
+
+	for i := 0; i < 2; i++ {
+		s, err := snake.NewSnake(w, profile.NewDefaultProfile(), nil)
+		require.Nil(t, err)
+
+		snakeStop := make(chan struct{})
+		done := p.processSnakeCommands(stop, snakeStop, chin, chout, s)
+		for j := 0; j < commandErrorsLimit; j++ {
+			chin <- "unknown"
+		}
+		close(snakeStop)
+		<-done
+	}
+
+	require.Len(t, chout, commandErrorsLimit, "new snake does not reset limit of errors")
+}
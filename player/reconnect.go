@@ -0,0 +1,203 @@
+package player
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ivan1993spb/snake-server/objects/snake"
+)
+
+// Maximal reconnection grace period
+const maxReconnectGrace = time.Minute * 5
+
+// Default period during which snake of disconnected player stays in game
+const defaultReconnectGrace = time.Second * 10
+
+// Reconnect contains settings of keeping snakes of disconnected players
+type Reconnect struct {
+	// Grace is period during which disconnected player can resume control of the snake. Zero disables reconnection
+	Grace time.Duration
+	// Freeze means that snake of disconnected player stays in place instead of moving straight
+	Freeze bool
+}
+
+// DefaultReconnect returns settings of keeping moving snakes of disconnected players for a short period
+func DefaultReconnect() Reconnect {
+	return Reconnect{
+		Grace: defaultReconnectGrace,
+	}
+}
+
+type ErrInvalidReconnect string
+
+func (e ErrInvalidReconnect) Error() string {
+	return "invalid reconnect: " + string(e)
+}
+
+// Validate checks reconnection settings
+func (r Reconnect) Validate() error {
+	if r.Grace < 0 {
+		return ErrInvalidReconnect("negative grace period")
+	}
+	if r.Grace > maxReconnectGrace {
+		return ErrInvalidReconnect("grace period is too long")
+	}
+	return nil
+}
+
+// running is living snake of player
+type running struct {
+	snake *snake.Snake
+	// done is closed when snake dies
+	done <-chan struct{}
+	// stop kills snake
+	stop func()
+	// lives is count of lives left to player
+	lives int
+}
+
+func runSnake(s *snake.Snake, lives int) *running {
+	stop := make(chan struct{})
+	once := &sync.Once{}
+
+	return &running{
+		snake: s,
+		done:  s.Run(stop),
+		stop: func() {
+			once.Do(func() {
+				close(stop)
+			})
+		},
+		lives: lives,
+	}
+}
+
+// Parking keeps snakes of disconnected players during reconnection grace period
+type Parking struct {
+	reconnect Reconnect
+
+	snakes map[string]*parkedSnake
+	closed bool
+	mux    *sync.Mutex
+}
+
+type parkedSnake struct {
+	*running
+	taken chan struct{}
+}
+
+func NewParking(reconnect Reconnect) *Parking {
+	return &Parking{
+		reconnect: reconnect,
+		snakes:    map[string]*parkedSnake{},
+		mux:       &sync.Mutex{},
+	}
+}
+
+// Start kills all parked snakes when the game stops
+func (p *Parking) Start(stop <-chan struct{}) {
+	go func() {
+		<-stop
+
+		p.mux.Lock()
+		defer p.mux.Unlock()
+
+		p.closed = true
+		for id, parked := range p.snakes {
+			delete(p.snakes, id)
+			close(parked.taken)
+			parked.stop()
+		}
+	}()
+}
+
+// park keeps snake of disconnected player with passed id. Snake is killed if grace period is over or parking is
+// disabled
+func (p *Parking) park(id string, r *running) {
+	if p == nil || p.reconnect.Grace <= 0 || id == "" {
+		r.stop()
+		return
+	}
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	if p.closed {
+		r.stop()
+		return
+	}
+
+	if previous, ok := p.snakes[id]; ok {
+		close(previous.taken)
+		previous.stop()
+	}
+
+	parked := &parkedSnake{
+		running: r,
+		taken:   make(chan struct{}),
+	}
+	p.snakes[id] = parked
+
+	if p.reconnect.Freeze {
+		r.snake.Freeze(true)
+	}
+
+	go p.wait(id, parked)
+}
+
+// wait removes parked snake when grace period is over or the snake dies
+func (p *Parking) wait(id string, parked *parkedSnake) {
+	timer := time.NewTimer(p.reconnect.Grace)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-parked.done:
+	case <-parked.taken:
+		return
+	}
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	if p.snakes[id] == parked {
+		delete(p.snakes, id)
+		close(parked.taken)
+		parked.stop()
+	}
+}
+
+// take returns parked snake of player with passed id to the player
+func (p *Parking) take(id string) (*running, bool) {
+	if p == nil || id == "" {
+		return nil, false
+	}
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	parked, ok := p.snakes[id]
+	if !ok {
+		return nil, false
+	}
+
+	delete(p.snakes, id)
+	close(parked.taken)
+
+	select {
+	case <-parked.done:
+		return nil, false
+	default:
+	}
+
+	parked.snake.Freeze(false)
+
+	return parked.running, true
+}
+
+// Count returns count of parked snakes
+func (p *Parking) Count() int {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	return len(p.snakes)
+}
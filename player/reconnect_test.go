@@ -0,0 +1,87 @@
+package player
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/profile"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+func Test_Parking_take_ResumesParkedSnake(t *testing.T) {
+	w, err := world.NewWorld(50, 50)
+	require.Nil(t, err)
+
+	parking := NewParking(Reconnect{
+		Grace:  time.Minute,
+		Freeze: true,
+	})
+
+	s, err := snake.NewSnake(w, profile.NewDefaultProfile(), nil)
+	require.Nil(t, err)
+
+	r := runSnake(s, 2)
+	defer r.stop()
+
+	parking.park("player", r)
+	require.Equal(t, 1, parking.Count())
+	require.True(t, s.Frozen())
+
+	_, ok := parking.take("another player")
+	require.False(t, ok)
+
+	resumed, ok := parking.take("player")
+	require.True(t, ok)
+	require.Equal(t, s, resumed.snake)
+	require.Equal(t, 2, resumed.lives)
+	require.False(t, s.Frozen())
+	require.Equal(t, 0, parking.Count())
+}
+
+func Test_Parking_park_KillsSnakeAfterGracePeriod(t *testing.T) {
+	w, err := world.NewWorld(50, 50)
+	require.Nil(t, err)
+
+	parking := NewParking(Reconnect{
+		Grace: time.Millisecond * 50,
+	})
+
+	s, err := snake.NewSnake(w, profile.NewDefaultProfile(), nil)
+	require.Nil(t, err)
+
+	r := runSnake(s, 0)
+	parking.park("player", r)
+
+	select {
+	case <-r.done:
+	case <-time.After(time.Second):
+		t.Fatal("snake is alive after grace period")
+	}
+
+	_, ok := parking.take("player")
+	require.False(t, ok)
+}
+
+func Test_Parking_park_KillsSnakeIfReconnectIsDisabled(t *testing.T) {
+	w, err := world.NewWorld(50, 50)
+	require.Nil(t, err)
+
+	parking := NewParking(Reconnect{})
+
+	s, err := snake.NewSnake(w, profile.NewDefaultProfile(), nil)
+	require.Nil(t, err)
+
+	r := runSnake(s, 0)
+	parking.park("player", r)
+
+	select {
+	case <-r.done:
+	case <-time.After(time.Second):
+		t.Fatal("snake is alive")
+	}
+
+	require.Equal(t, 0, parking.Count())
+}
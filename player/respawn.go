@@ -0,0 +1,87 @@
+package player
+
+import (
+	"time"
+
+	"github.com/ivan1993spb/snake-server/objects/snake"
+	"github.com/ivan1993spb/snake-server/profile"
+	"github.com/ivan1993spb/snake-server/teams"
+	"github.com/ivan1993spb/snake-server/world"
+)
+
+// Default delay before player gets new snake
+const defaultRespawnDelay = time.Second * 5
+
+// Respawn contains settings of giving snakes to players
+type Respawn struct {
+	// Delay is countdown before player gets new snake
+	Delay time.Duration
+	// Lives is count of snakes which player gets. Zero means unlimited lives
+	Lives int
+	// Manual means that dead player gets new snake only on respawn command
+	Manual bool
+	// Protection is duration of invulnerability of new snake
+	Protection time.Duration
+	// SafeDistance is minimal distance in dots from new snake to heads of other snakes. Zero disables the check
+	SafeDistance uint8
+	// Turns contains settings of queue of turns of new snake
+	Turns snake.TurnQueue
+}
+
+// DefaultRespawn returns settings of automatic respawn with unlimited lives
+func DefaultRespawn() Respawn {
+	return Respawn{
+		Delay: defaultRespawnDelay,
+		Turns: snake.DefaultTurnQueue(),
+	}
+}
+
+type ErrInvalidRespawn string
+
+func (e ErrInvalidRespawn) Error() string {
+	return "invalid respawn: " + string(e)
+}
+
+// Validate checks respawn settings
+func (r Respawn) Validate() error {
+	if r.Delay < 0 {
+		return ErrInvalidRespawn("negative delay")
+	}
+	if r.Lives < 0 {
+		return ErrInvalidRespawn("negative lives")
+	}
+	if r.Protection < 0 {
+		return ErrInvalidRespawn("negative protection")
+	}
+	return r.Turns.Validate()
+}
+
+// Spawn creates new snake with respect to spawn safety, protection and turn queue. Team may be nil
+func (r Respawn) Spawn(w *world.World, p *profile.Profile, team *teams.Team) (*snake.Snake, error) {
+	var (
+		s   *snake.Snake
+		err error
+	)
+
+	if r.SafeDistance > 0 {
+		s, err = snake.NewSafeSnake(w, p, team, r.SafeDistance)
+	} else {
+		s, err = snake.NewSnake(w, p, team)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Protection > 0 {
+		s.Protect(r.Protection)
+	}
+
+	s.SetTurnQueue(r.Turns)
+
+	return s, nil
+}
+
+// countdown returns respawn delay in seconds
+func (r Respawn) countdown() uint {
+	return uint((r.Delay + time.Second - 1) / time.Second)
+}
@@ -0,0 +1,21 @@
+package player
+
+import "github.com/ivan1993spb/snake-server/world"
+
+// Spectate returns messages of spectator: playground size and objects. Spectator never gets snake
+func Spectate(stop <-chan struct{}, world *world.World) <-chan Message {
+	chout := make(chan Message, chanMessageBuffer)
+
+	go func() {
+		defer close(chout)
+
+		chout <- NewMessageNotice("welcome to snake server!")
+		chout <- NewMessageSize(world.Width(), world.Height())
+		chout <- NewMessageObjects(append(world.GetObjects(), world.GetRegions()...))
+		chout <- NewMessageSpectator("watching")
+
+		<-stop
+	}()
+
+	return chout
+}
@@ -21,6 +21,8 @@ type Playground struct {
 	scene         *engine.Scene
 	entities      []entity
 	entitiesMutex *sync.RWMutex
+	regions       []entity
+	regionsMutex  *sync.RWMutex
 }
 
 func NewPlayground(width, height uint8) (*Playground, error) {
@@ -33,6 +35,8 @@ func NewPlayground(width, height uint8) (*Playground, error) {
 		scene:         scene,
 		entities:      []entity{},
 		entitiesMutex: &sync.RWMutex{},
+		regions:       []entity{},
+		regionsMutex:  &sync.RWMutex{},
 	}, nil
 }
 
@@ -696,10 +700,22 @@ func (pg *Playground) CreateObjectRandomByDotsMask(object interface{}, dm *engin
 	return location.Copy(), nil
 }
 
+func (pg *Playground) DotOccupied(dot engine.Dot) bool {
+	return pg.scene.DotOccupied(dot)
+}
+
 func (pg *Playground) Navigate(dot engine.Dot, dir engine.Direction, dis uint8) (engine.Dot, error) {
 	return pg.scene.Navigate(dot, dir, dis)
 }
 
+func (pg *Playground) Shift(dot engine.Dot, dx, dy int) (engine.Dot, error) {
+	return pg.scene.Shift(dot, dx, dy)
+}
+
+func (pg *Playground) RandomRect(rw, rh uint8) (*engine.Rect, error) {
+	return pg.scene.RandomRect(rw, rh)
+}
+
 func (pg *Playground) Size() uint16 {
 	return pg.scene.Size()
 }
@@ -720,6 +736,28 @@ func (pg *Playground) unsafeGetObjects() []interface{} {
 	return objects
 }
 
+// Clear deletes all objects and regions from playground and returns deleted objects and regions
+func (pg *Playground) Clear() []interface{} {
+	pg.entitiesMutex.Lock()
+	defer pg.entitiesMutex.Unlock()
+	pg.regionsMutex.Lock()
+	defer pg.regionsMutex.Unlock()
+
+	objects := make([]interface{}, 0, len(pg.entities)+len(pg.regions))
+	for _, e := range pg.entities {
+		objects = append(objects, e.object)
+	}
+	for _, r := range pg.regions {
+		objects = append(objects, r.object)
+	}
+
+	pg.scene.Clear()
+	pg.entities = []entity{}
+	pg.regions = []entity{}
+
+	return objects
+}
+
 func (pg *Playground) GetObjects() []interface{} {
 	pg.entitiesMutex.RLock()
 	defer pg.entitiesMutex.RUnlock()
@@ -277,3 +277,21 @@ func Test_Playground_UpdateObjectAvailableDots_SuccessfullyUpdates(t *testing.T)
 		engine.Dot{0, 3},
 	}, actualLocation)
 }
+
+func Test_Playground_Clear(t *testing.T) {
+	pg, err := NewPlayground(20, 20)
+	require.Nil(t, err)
+
+	object := &struct{}{}
+	region := &struct{}{}
+	dot := engine.Dot{X: 1, Y: 1}
+
+	require.Nil(t, pg.CreateObject(object, engine.Location{dot}))
+	require.Nil(t, pg.CreateRegion(region, engine.Location{dot}))
+
+	objects := pg.Clear()
+	require.Len(t, objects, 2)
+	require.Empty(t, pg.GetObjects())
+	require.Empty(t, pg.GetRegions())
+	require.False(t, pg.DotOccupied(dot))
+}
@@ -0,0 +1,122 @@
+package playground
+
+import (
+	"github.com/ivan1993spb/snake-server/engine"
+)
+
+// Regions are non-blocking areas of playground. Regions are not located on scene: objects move through regions
+// freely and regions may overlap each other and objects
+
+type ErrRegion string
+
+func (e ErrRegion) Error() string {
+	return "region error: " + string(e)
+}
+
+const (
+	ErrRegionExists      = ErrRegion("region exists")
+	ErrRegionNotFound    = ErrRegion("region not found")
+	ErrRegionOutOfBounds = ErrRegion("region location is out of playground")
+)
+
+func (pg *Playground) unsafeRegionIndex(object interface{}) int {
+	for i := range pg.regions {
+		if pg.regions[i].object == object {
+			return i
+		}
+	}
+	return -1
+}
+
+func (pg *Playground) validRegionLocation(location engine.Location) error {
+	if location.Empty() {
+		return ErrEmptyLocation
+	}
+	for _, dot := range location {
+		if dot.X >= pg.Width() || dot.Y >= pg.Height() {
+			return ErrRegionOutOfBounds
+		}
+	}
+	return nil
+}
+
+// CreateRegion adds non-blocking region with passed location
+func (pg *Playground) CreateRegion(object interface{}, location engine.Location) error {
+	if err := pg.validRegionLocation(location); err != nil {
+		return err
+	}
+
+	pg.regionsMutex.Lock()
+	defer pg.regionsMutex.Unlock()
+
+	if pg.unsafeRegionIndex(object) > -1 {
+		return ErrRegionExists
+	}
+
+	pg.regions = append(pg.regions, entity{
+		object:   object,
+		location: location.Copy(),
+	})
+
+	return nil
+}
+
+// UpdateRegion moves region to new location
+func (pg *Playground) UpdateRegion(object interface{}, location engine.Location) error {
+	if err := pg.validRegionLocation(location); err != nil {
+		return err
+	}
+
+	pg.regionsMutex.Lock()
+	defer pg.regionsMutex.Unlock()
+
+	i := pg.unsafeRegionIndex(object)
+	if i == -1 {
+		return ErrRegionNotFound
+	}
+
+	pg.regions[i].location = location.Copy()
+
+	return nil
+}
+
+// DeleteRegion removes region
+func (pg *Playground) DeleteRegion(object interface{}) error {
+	pg.regionsMutex.Lock()
+	defer pg.regionsMutex.Unlock()
+
+	i := pg.unsafeRegionIndex(object)
+	if i == -1 {
+		return ErrRegionNotFound
+	}
+
+	pg.regions = append(pg.regions[:i], pg.regions[i+1:]...)
+
+	return nil
+}
+
+// GetRegionsByDot returns regions which contain passed dot
+func (pg *Playground) GetRegionsByDot(dot engine.Dot) []interface{} {
+	pg.regionsMutex.RLock()
+	defer pg.regionsMutex.RUnlock()
+
+	var regions []interface{}
+	for _, region := range pg.regions {
+		if region.location.Contains(dot) {
+			regions = append(regions, region.object)
+		}
+	}
+	return regions
+}
+
+// GetRegions returns all regions
+func (pg *Playground) GetRegions() []interface{} {
+	pg.regionsMutex.RLock()
+	defer pg.regionsMutex.RUnlock()
+
+	regions := make([]interface{}, len(pg.regions))
+	for i, region := range pg.regions {
+		regions[i] = region.object
+	}
+	return regions
+}
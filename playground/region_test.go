@@ -0,0 +1,47 @@
+package playground
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/engine"
+)
+
+func Test_Playground_CreateRegion_DoesNotBlockObjects(t *testing.T) {
+	pg, err := NewPlayground(20, 20)
+	require.Nil(t, err)
+
+	region := &struct{ int }{1}
+	location := engine.NewRect(2, 2, 3, 3).Location()
+
+	require.Nil(t, pg.CreateRegion(region, location))
+	require.Equal(t, ErrRegionExists, pg.CreateRegion(region, location))
+	require.False(t, pg.DotOccupied(engine.Dot{X: 3, Y: 3}))
+
+	object := &struct{ int }{2}
+	require.Nil(t, pg.CreateObject(object, engine.Location{{X: 3, Y: 3}}))
+
+	require.Equal(t, []interface{}{region}, pg.GetRegionsByDot(engine.Dot{X: 3, Y: 3}))
+	require.Empty(t, pg.GetRegionsByDot(engine.Dot{X: 10, Y: 10}))
+	require.Equal(t, []interface{}{object}, pg.GetObjects())
+}
+
+func Test_Playground_UpdateRegion(t *testing.T) {
+	pg, err := NewPlayground(20, 20)
+	require.Nil(t, err)
+
+	region := &struct{ int }{1}
+	require.Equal(t, ErrRegionNotFound, pg.UpdateRegion(region, engine.Location{{X: 1, Y: 1}}))
+
+	require.Nil(t, pg.CreateRegion(region, engine.Location{{X: 1, Y: 1}}))
+	require.Equal(t, ErrRegionOutOfBounds, pg.UpdateRegion(region, engine.Location{{X: 25, Y: 1}}))
+	require.Nil(t, pg.UpdateRegion(region, engine.Location{{X: 5, Y: 5}}))
+
+	require.Empty(t, pg.GetRegionsByDot(engine.Dot{X: 1, Y: 1}))
+	require.Equal(t, []interface{}{region}, pg.GetRegionsByDot(engine.Dot{X: 5, Y: 5}))
+
+	require.Nil(t, pg.DeleteRegion(region))
+	require.Empty(t, pg.GetRegions())
+	require.Equal(t, ErrRegionNotFound, pg.DeleteRegion(region))
+}
@@ -0,0 +1,85 @@
+package profile
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	DefaultNickname = "anonymous"
+
+	nicknameMaxLength = 16
+
+	// Clients know skins with identifiers from 0 to SkinsLimit-1
+	SkinsLimit = 16
+)
+
+// Allowed nickname characters apart from letters and digits
+const nicknameSpecialChars = " _-."
+
+var colorRegexp = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Profile contains player cosmetics
+type Profile struct {
+	// ID is stable identifier of player. It is empty if player has no session
+	ID       string `json:"id,omitempty"`
+	Nickname string `json:"nickname"`
+	Color    string `json:"color,omitempty"`
+	Skin     uint8  `json:"skin"`
+	// Bot means that snakes of player are controlled by server
+	Bot bool `json:"bot,omitempty"`
+}
+
+type ErrInvalidProfile string
+
+func (e ErrInvalidProfile) Error() string {
+	return "invalid profile: " + string(e)
+}
+
+var (
+	ErrNicknameTooLong     = ErrInvalidProfile("nickname is too long")
+	ErrNicknameInvalidChar = ErrInvalidProfile("nickname contains invalid characters")
+	ErrInvalidColor        = ErrInvalidProfile("color must be in format #rrggbb")
+	ErrInvalidSkin         = ErrInvalidProfile("unknown skin")
+)
+
+// NewProfile validates passed data and creates profile. Empty nickname is replaced with default nickname
+func NewProfile(nickname, color string, skin uint8) (*Profile, error) {
+	nickname = strings.TrimSpace(nickname)
+	if nickname == "" {
+		nickname = DefaultNickname
+	}
+
+	if utf8.RuneCountInString(nickname) > nicknameMaxLength {
+		return nil, ErrNicknameTooLong
+	}
+
+	for _, r := range nickname {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(nicknameSpecialChars, r) {
+			return nil, ErrNicknameInvalidChar
+		}
+	}
+
+	if color != "" && !colorRegexp.MatchString(color) {
+		return nil, ErrInvalidColor
+	}
+
+	if skin >= SkinsLimit {
+		return nil, ErrInvalidSkin
+	}
+
+	return &Profile{
+		Nickname: nickname,
+		Color:    strings.ToLower(color),
+		Skin:     skin,
+	}, nil
+}
+
+// NewDefaultProfile returns profile with default nickname
+func NewDefaultProfile() *Profile {
+	return &Profile{
+		Nickname: DefaultNickname,
+	}
+}
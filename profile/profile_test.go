@@ -0,0 +1,48 @@
+package profile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewProfile_CreatesValidProfile(t *testing.T) {
+	p, err := NewProfile(" Ivan_1993 ", "#FFaa00", 3)
+	require.Nil(t, err)
+	require.Equal(t, &Profile{
+		Nickname: "Ivan_1993",
+		Color:    "#ffaa00",
+		Skin:     3,
+	}, p)
+}
+
+func Test_NewProfile_UsesDefaultNickname(t *testing.T) {
+	p, err := NewProfile("", "", 0)
+	require.Nil(t, err)
+	require.Equal(t, DefaultNickname, p.Nickname)
+}
+
+func Test_NewProfile_AcceptsUnicodeLetters(t *testing.T) {
+	p, err := NewProfile("Иван", "", 0)
+	require.Nil(t, err)
+	require.Equal(t, "Иван", p.Nickname)
+}
+
+func Test_NewProfile_ReturnsErrors(t *testing.T) {
+	var err error
+
+	_, err = NewProfile("very long nickname for snake", "", 0)
+	require.Equal(t, ErrNicknameTooLong, err)
+
+	_, err = NewProfile("<script>", "", 0)
+	require.Equal(t, ErrNicknameInvalidChar, err)
+
+	_, err = NewProfile("snake", "red", 0)
+	require.Equal(t, ErrInvalidColor, err)
+
+	_, err = NewProfile("snake", "#ff00000", 0)
+	require.Equal(t, ErrInvalidColor, err)
+
+	_, err = NewProfile("snake", "", SkinsLimit)
+	require.Equal(t, ErrInvalidSkin, err)
+}
@@ -0,0 +1,316 @@
+// Package sessions keeps player sessions. A session binds token presented by player on connections to stable player
+// identifier and profile.
+package sessions
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pquerna/ffjson/ffjson"
+	"github.com/satori/go.uuid"
+
+	"github.com/ivan1993spb/snake-server/profile"
+)
+
+const tokenLength = 16
+
+// Sessions which are not used for sessionLifetime are dropped
+const sessionLifetime = time.Hour * 24
+
+// Use of persisted session is written to log not more often than once per sessionLogInterval
+const sessionLogInterval = time.Hour
+
+type ErrSession string
+
+func (e ErrSession) Error() string {
+	return "session error: " + string(e)
+}
+
+const ErrSessionNotFound = ErrSession("session not found")
+
+// Session binds token to player identifier and profile
+type Session struct {
+	token    string
+	playerID string
+	profile  *profile.Profile
+	lastSeen time.Time
+	// logged is time when session was written to log last time
+	logged time.Time
+	mux    *sync.RWMutex
+}
+
+func (s *Session) Token() string {
+	return s.token
+}
+
+func (s *Session) PlayerID() string {
+	return s.playerID
+}
+
+// Profile returns copy of player profile with player identifier
+func (s *Session) Profile() *profile.Profile {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	p := *s.profile
+	return &p
+}
+
+// SetProfile replaces player profile
+func (s *Session) SetProfile(p *profile.Profile) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	copied := *p
+	copied.ID = s.playerID
+	s.profile = &copied
+}
+
+func (s *Session) touch(now time.Time) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.lastSeen = now
+}
+
+func (s *Session) loggedBefore(t time.Time) bool {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.logged.Before(t)
+}
+
+func (s *Session) expired(now time.Time) bool {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return now.Sub(s.lastSeen) > sessionLifetime
+}
+
+func (s *Session) MarshalJSON() ([]byte, error) {
+	return ffjson.Marshal(&session{
+		Token:    s.token,
+		PlayerID: s.playerID,
+		Profile:  s.Profile(),
+	})
+}
+
+type session struct {
+	Token    string           `json:"token"`
+	PlayerID string           `json:"player_id"`
+	Profile  *profile.Profile `json:"profile"`
+}
+
+// record is line of sessions log. The last record of token wins
+type record struct {
+	Token    string           `json:"token"`
+	PlayerID string           `json:"player_id"`
+	Profile  *profile.Profile `json:"profile"`
+	Seen     time.Time        `json:"seen"`
+}
+
+// Store keeps sessions by tokens
+type Store struct {
+	sessions map[string]*Session
+	// file is nil if sessions are not persisted
+	file *os.File
+	mux  *sync.Mutex
+}
+
+// NewStore creates store which keeps sessions in memory only
+func NewStore() *Store {
+	return &Store{
+		sessions: map[string]*Session{},
+		mux:      &sync.Mutex{},
+	}
+}
+
+// Open loads sessions from log file at passed path and appends changes of sessions to the file, so players keep
+// their identifiers after server restart. Empty path means that sessions are kept in memory only
+func Open(path string) (*Store, error) {
+	s := NewStore()
+
+	if path == "" {
+		return s, nil
+	}
+
+	// Log contains tokens of players
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, ErrSession("cannot open log: " + err.Error())
+	}
+
+	if err := s.load(file); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	s.file = file
+
+	return s, nil
+}
+
+func (s *Store) load(file *os.File) error {
+	now := time.Now()
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var r record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil || r.Token == "" || r.Profile == nil {
+			// Skip broken line, for example if server was stopped during writing
+			continue
+		}
+
+		session := &Session{
+			token:    r.Token,
+			playerID: r.PlayerID,
+			lastSeen: r.Seen,
+			logged:   r.Seen,
+			mux:      &sync.RWMutex{},
+		}
+		session.SetProfile(r.Profile)
+
+		if session.expired(now) {
+			delete(s.sessions, r.Token)
+			continue
+		}
+
+		s.sessions[r.Token] = session
+	}
+
+	if err := scanner.Err(); err != nil {
+		return ErrSession("cannot read log: " + err.Error())
+	}
+
+	return nil
+}
+
+// unsafeSave appends session to log if sessions are persisted
+func (s *Store) unsafeSave(session *Session, now time.Time) error {
+	if s.file == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(&record{
+		Token:    session.token,
+		PlayerID: session.playerID,
+		Profile:  session.Profile(),
+		Seen:     now,
+	})
+	if err != nil {
+		return ErrSession("cannot encode session: " + err.Error())
+	}
+
+	if _, err := s.file.Write(append(data, '\n')); err != nil {
+		return ErrSession("cannot write log: " + err.Error())
+	}
+
+	session.mux.Lock()
+	session.logged = now
+	session.mux.Unlock()
+
+	return nil
+}
+
+// Create issues new session with new player identifier
+func (s *Store) Create(p *profile.Profile) (*Session, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, ErrSession(err.Error())
+	}
+
+	now := time.Now()
+	session := &Session{
+		token:    token,
+		playerID: uuid.Must(uuid.NewV4()).String(),
+		lastSeen: now,
+		mux:      &sync.RWMutex{},
+	}
+	session.SetProfile(p)
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.unsafeDropExpired(now)
+
+	if err := s.unsafeSave(session, now); err != nil {
+		return nil, err
+	}
+
+	s.sessions[token] = session
+
+	return session, nil
+}
+
+// Get returns session by token
+func (s *Store) Get(token string) (*Session, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	now := time.Now()
+
+	session, ok := s.sessions[token]
+	if !ok || session.expired(now) {
+		return nil, ErrSessionNotFound
+	}
+
+	session.touch(now)
+
+	if session.loggedBefore(now.Add(-sessionLogInterval)) {
+		// Session is valid even if its use cannot be written to log
+		s.unsafeSave(session, now)
+	}
+
+	return session, nil
+}
+
+// UpdateProfile replaces player profile of session and saves the session
+func (s *Store) UpdateProfile(session *Session, p *profile.Profile) error {
+	session.SetProfile(p)
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return s.unsafeSave(session, time.Now())
+}
+
+// Close closes log file
+func (s *Store) Close() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// Count returns count of sessions
+func (s *Store) Count() int {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return len(s.sessions)
+}
+
+func (s *Store) unsafeDropExpired(now time.Time) {
+	for token, session := range s.sessions {
+		if session.expired(now) {
+			delete(s.sessions, token)
+		}
+	}
+}
+
+func newToken() (string, error) {
+	buf := make([]byte, tokenLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
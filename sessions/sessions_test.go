@@ -0,0 +1,106 @@
+package sessions
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ivan1993spb/snake-server/profile"
+)
+
+func Test_Store_Create_IssuesUniqueSessions(t *testing.T) {
+	store := NewStore()
+
+	first, err := store.Create(profile.NewDefaultProfile())
+	require.Nil(t, err)
+	second, err := store.Create(profile.NewDefaultProfile())
+	require.Nil(t, err)
+
+	require.Len(t, first.Token(), tokenLength*2)
+	require.NotEqual(t, first.Token(), second.Token())
+	require.NotEqual(t, first.PlayerID(), second.PlayerID())
+	require.Equal(t, first.PlayerID(), first.Profile().ID)
+	require.Equal(t, 2, store.Count())
+}
+
+func Test_Store_Get_ReturnsSameSession(t *testing.T) {
+	store := NewStore()
+
+	p, err := profile.NewProfile("Ivan", "", 1)
+	require.Nil(t, err)
+
+	created, err := store.Create(p)
+	require.Nil(t, err)
+
+	session, err := store.Get(created.Token())
+	require.Nil(t, err)
+	require.Equal(t, created, session)
+	require.Equal(t, "Ivan", session.Profile().Nickname)
+
+	_, err = store.Get("unknown")
+	require.Equal(t, ErrSessionNotFound, err)
+}
+
+func Test_Session_SetProfile_KeepsPlayerID(t *testing.T) {
+	store := NewStore()
+
+	session, err := store.Create(profile.NewDefaultProfile())
+	require.Nil(t, err)
+
+	p, err := profile.NewProfile("Petr", "#ff0000", 2)
+	require.Nil(t, err)
+	session.SetProfile(p)
+
+	require.Equal(t, "Petr", session.Profile().Nickname)
+	require.Equal(t, session.PlayerID(), session.Profile().ID)
+	require.Empty(t, p.ID, "passed profile is not modified")
+}
+
+func Test_Store_Get_DropsExpiredSession(t *testing.T) {
+	store := NewStore()
+
+	session, err := store.Create(profile.NewDefaultProfile())
+	require.Nil(t, err)
+
+	session.touch(time.Now().Add(-sessionLifetime - time.Minute))
+
+	_, err = store.Get(session.Token())
+	require.Equal(t, ErrSessionNotFound, err)
+
+	_, err = store.Create(profile.NewDefaultProfile())
+	require.Nil(t, err)
+	require.Equal(t, 1, store.Count())
+}
+
+func Test_Store_Open_PersistsSessions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sessions")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "sessions.log")
+
+	store, err := Open(path)
+	require.Nil(t, err)
+
+	created, err := store.Create(profile.NewDefaultProfile())
+	require.Nil(t, err)
+
+	p, err := profile.NewProfile("Ivan", "", 1)
+	require.Nil(t, err)
+	require.Nil(t, store.UpdateProfile(created, p))
+	require.Nil(t, store.Close())
+
+	store, err = Open(path)
+	require.Nil(t, err)
+	defer store.Close()
+
+	session, err := store.Get(created.Token())
+	require.Nil(t, err)
+	require.Equal(t, created.PlayerID(), session.PlayerID())
+	require.Equal(t, "Ivan", session.Profile().Nickname)
+	require.Equal(t, created.PlayerID(), session.Profile().ID)
+}
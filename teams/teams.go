@@ -0,0 +1,198 @@
+// Package teams keeps teams of a game and membership of players.
+package teams
+
+import (
+	"sync"
+
+	"github.com/pquerna/ffjson/ffjson"
+)
+
+// Game may have from MinTeams to MaxTeams teams
+const (
+	MinTeams = 2
+	MaxTeams = 4
+)
+
+var palette = []struct {
+	name  string
+	color string
+}{
+	{"red", "#e53935"},
+	{"blue", "#1e88e5"},
+	{"green", "#43a047"},
+	{"yellow", "#fdd835"},
+}
+
+type ErrTeams string
+
+func (e ErrTeams) Error() string {
+	return "teams error: " + string(e)
+}
+
+const (
+	ErrInvalidTeamsCount = ErrTeams("invalid teams count")
+	ErrUnknownTeam       = ErrTeams("unknown team")
+)
+
+// Team is a group of players with common color and score
+type Team struct {
+	name         string
+	color        string
+	friendlyFire bool
+	members      int
+	kills        uint16
+	mux          *sync.RWMutex
+}
+
+func (t *Team) Name() string {
+	return t.name
+}
+
+func (t *Team) Color() string {
+	return t.color
+}
+
+// FriendlyFire returns true if teammates kill each other on touch
+func (t *Team) FriendlyFire() bool {
+	return t.friendlyFire
+}
+
+// Members returns count of players in team
+func (t *Team) Members() int {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+	return t.members
+}
+
+// Kills returns count of enemy snakes killed by team members
+func (t *Team) Kills() uint16 {
+	t.mux.RLock()
+	defer t.mux.RUnlock()
+	return t.kills
+}
+
+func (t *Team) AddKill() {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.kills++
+}
+
+func (t *Team) String() string {
+	return "team " + t.name
+}
+
+func (t *Team) MarshalJSON() ([]byte, error) {
+	return ffjson.Marshal(&team{
+		Name:  t.name,
+		Color: t.color,
+	})
+}
+
+type team struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// Teammates returns true if both teams are the same team
+func Teammates(first, second *Team) bool {
+	return first != nil && first == second
+}
+
+// Teams contains teams of a game
+type Teams struct {
+	teams []*Team
+	mux   *sync.Mutex
+}
+
+// NewTeams creates passed count of teams. If friendlyFire is false, teammates do not kill each other
+func NewTeams(count int, friendlyFire bool) (*Teams, error) {
+	if count < MinTeams || count > MaxTeams {
+		return nil, ErrInvalidTeamsCount
+	}
+
+	teams := make([]*Team, count)
+	for i := range teams {
+		teams[i] = &Team{
+			name:         palette[i].name,
+			color:        palette[i].color,
+			friendlyFire: friendlyFire,
+			mux:          &sync.RWMutex{},
+		}
+	}
+
+	return &Teams{
+		teams: teams,
+		mux:   &sync.Mutex{},
+	}, nil
+}
+
+// Join adds player to team with passed name. If name is empty the team with the fewest members is chosen
+func (t *Teams) Join(name string) (*Team, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	var chosen *Team
+
+	if name == "" {
+		for _, team := range t.teams {
+			if chosen == nil || team.Members() < chosen.Members() {
+				chosen = team
+			}
+		}
+	} else {
+		chosen = t.get(name)
+	}
+
+	if chosen == nil {
+		return nil, ErrUnknownTeam
+	}
+
+	chosen.mux.Lock()
+	chosen.members++
+	chosen.mux.Unlock()
+
+	return chosen, nil
+}
+
+// Leave removes player from team
+func (t *Teams) Leave(team *Team) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	team.mux.Lock()
+	if team.members > 0 {
+		team.members--
+	}
+	team.mux.Unlock()
+}
+
+func (t *Teams) get(name string) *Team {
+	for _, team := range t.teams {
+		if team.name == name {
+			return team
+		}
+	}
+	return nil
+}
+
+// Get returns team by name
+func (t *Teams) Get(name string) (*Team, bool) {
+	team := t.get(name)
+	return team, team != nil
+}
+
+// List returns all teams
+func (t *Teams) List() []*Team {
+	teams := make([]*Team, len(t.teams))
+	copy(teams, t.teams)
+	return teams
+}
+
+// Names returns names of all teams
+func (t *Teams) Names() []string {
+	names := make([]string, len(t.teams))
+	for i, team := range t.teams {
+		names[i] = team.name
+	}
+	return names
+}
@@ -0,0 +1,68 @@
+package teams
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewTeams_ValidatesCount(t *testing.T) {
+	_, err := NewTeams(1, true)
+	require.Equal(t, ErrInvalidTeamsCount, err)
+
+	_, err = NewTeams(MaxTeams+1, true)
+	require.Equal(t, ErrInvalidTeamsCount, err)
+
+	teams, err := NewTeams(3, false)
+	require.Nil(t, err)
+	require.Equal(t, []string{"red", "blue", "green"}, teams.Names())
+	require.False(t, teams.List()[0].FriendlyFire())
+}
+
+func Test_Teams_Join_BalancesTeams(t *testing.T) {
+	teams, err := NewTeams(2, true)
+	require.Nil(t, err)
+
+	red, err := teams.Join("")
+	require.Nil(t, err)
+	require.Equal(t, "red", red.Name())
+
+	blue, err := teams.Join("")
+	require.Nil(t, err)
+	require.Equal(t, "blue", blue.Name())
+
+	red2, err := teams.Join("red")
+	require.Nil(t, err)
+	require.Equal(t, red, red2)
+	require.Equal(t, 2, red.Members())
+
+	team, err := teams.Join("")
+	require.Nil(t, err)
+	require.Equal(t, blue, team)
+
+	teams.Leave(red)
+	teams.Leave(red)
+	team, err = teams.Join("")
+	require.Nil(t, err)
+	require.Equal(t, red, team)
+}
+
+func Test_Teams_Join_UnknownTeam(t *testing.T) {
+	teams, err := NewTeams(2, true)
+	require.Nil(t, err)
+
+	_, err = teams.Join("yellow")
+	require.Equal(t, ErrUnknownTeam, err)
+}
+
+func Test_Teammates(t *testing.T) {
+	teams, err := NewTeams(2, true)
+	require.Nil(t, err)
+
+	red, _ := teams.Get("red")
+	blue, _ := teams.Get("blue")
+
+	require.True(t, Teammates(red, red))
+	require.False(t, Teammates(red, blue))
+	require.False(t, Teammates(nil, nil))
+}
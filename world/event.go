@@ -8,6 +8,7 @@ const (
 	EventTypeObjectDelete
 	EventTypeObjectUpdate
 	EventTypeObjectChecked
+	EventTypeExplosion
 )
 
 var eventsLabels = map[EventType]string{
@@ -16,6 +17,7 @@ var eventsLabels = map[EventType]string{
 	EventTypeObjectDelete:  "delete",
 	EventTypeObjectUpdate:  "update",
 	EventTypeObjectChecked: "checked",
+	EventTypeExplosion:     "explosion",
 }
 
 func (event EventType) String() string {
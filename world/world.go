@@ -63,7 +63,6 @@ func (w *World) Start(stop <-chan struct{}) {
 	go func() {
 		for {
 			select {
-			// TODO: fix case if chMain closed
 			case event := <-w.chMain:
 				w.broadcast(event)
 			case <-w.stopGlobal:
@@ -73,6 +72,12 @@ func (w *World) Start(stop <-chan struct{}) {
 	}()
 }
 
+// Done returns channel which is closed when world is stopped. Objects which outlive objects created them, like trails
+// and placed bombs, run until world is stopped
+func (w *World) Done() <-chan struct{} {
+	return w.stopGlobal
+}
+
 func (w *World) broadcast(event Event) {
 	w.chsProxyMux.RLock()
 	defer w.chsProxyMux.RUnlock()
@@ -166,9 +171,10 @@ func (w *World) sendEvent(ch chan Event, event Event, stop <-chan struct{}, time
 	}
 }
 
+// stop stops broadcasting of events. Main channel is not closed because objects may still create events after world
+// is stopped: such events are dropped
 func (w *World) stop() {
 	close(w.stopGlobal)
-	close(w.chMain)
 
 	w.chsProxyMux.Lock()
 	defer w.chsProxyMux.Unlock()
@@ -381,10 +387,106 @@ func (w *World) CreateObjectRandomByDotsMask(object interface{}, dm *engine.Dots
 	return location, err
 }
 
+func (w *World) DotOccupied(dot engine.Dot) bool {
+	return w.pg.DotOccupied(dot)
+}
+
+// Clear deletes all objects and regions from playground
+func (w *World) Clear() {
+	for _, object := range w.pg.Clear() {
+		w.event(Event{
+			Type:    EventTypeObjectDelete,
+			Payload: object,
+		})
+	}
+}
+
+// FreeDots returns dots of location which are not occupied by objects
+func (w *World) FreeDots(location engine.Location) engine.Location {
+	free := make(engine.Location, 0, len(location))
+	for _, dot := range location {
+		if !w.pg.DotOccupied(dot) {
+			free = append(free, dot)
+		}
+	}
+	return free
+}
+
+// CreateRegion adds non-blocking region to playground
+func (w *World) CreateRegion(object interface{}, location engine.Location) error {
+	if err := w.pg.CreateRegion(object, location); err != nil {
+		w.event(Event{
+			Type:    EventTypeError,
+			Payload: err,
+		})
+		return err
+	}
+	w.event(Event{
+		Type:    EventTypeObjectCreate,
+		Payload: object,
+	})
+	return nil
+}
+
+// UpdateRegion moves region or notifies listeners that region state was changed if location is the same
+func (w *World) UpdateRegion(object interface{}, location engine.Location) error {
+	if err := w.pg.UpdateRegion(object, location); err != nil {
+		w.event(Event{
+			Type:    EventTypeError,
+			Payload: err,
+		})
+		return err
+	}
+	w.event(Event{
+		Type:    EventTypeObjectUpdate,
+		Payload: object,
+	})
+	return nil
+}
+
+func (w *World) DeleteRegion(object interface{}) error {
+	if err := w.pg.DeleteRegion(object); err != nil {
+		w.event(Event{
+			Type:    EventTypeError,
+			Payload: err,
+		})
+		return err
+	}
+	w.event(Event{
+		Type:    EventTypeObjectDelete,
+		Payload: object,
+	})
+	return nil
+}
+
+func (w *World) GetRegionsByDot(dot engine.Dot) []interface{} {
+	return w.pg.GetRegionsByDot(dot)
+}
+
+func (w *World) GetRegions() []interface{} {
+	return w.pg.GetRegions()
+}
+
+func (w *World) RandomRect(rw, rh uint8) (*engine.Rect, error) {
+	return w.pg.RandomRect(rw, rh)
+}
+
+// Explode notifies world listeners about explosion
+func (w *World) Explode(explosion interface{}) {
+	w.event(Event{
+		Type:    EventTypeExplosion,
+		Payload: explosion,
+	})
+}
+
 func (w *World) Navigate(dot engine.Dot, dir engine.Direction, dis uint8) (engine.Dot, error) {
 	return w.pg.Navigate(dot, dir, dis)
 }
 
+func (w *World) Shift(dot engine.Dot, dx, dy int) (engine.Dot, error) {
+	return w.pg.Shift(dot, dx, dy)
+}
+
 func (w *World) Size() uint16 {
 	return w.pg.Size()
 }
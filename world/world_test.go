@@ -101,3 +101,25 @@ func Test_World_UpdateObject(t *testing.T) {
 func Benchmark_World_UpdateObject(b *testing.B) {
 	// TODO: Implement benchmark.
 }
+
+func Test_World_CreateObject_AfterStop(t *testing.T) {
+	world, err := NewWorld(10, 10)
+	require.Nil(t, err)
+
+	stop := make(chan struct{})
+	world.Start(stop)
+	close(stop)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := uint8(0); i < 10; i++ {
+			require.Nil(t, world.CreateObject(new(int), engine.Location{engine.Dot{X: i, Y: 0}}))
+		}
+	}()
+
+	for i := uint8(0); i < 10; i++ {
+		require.Nil(t, world.CreateObject(new(int), engine.Location{engine.Dot{X: i, Y: 1}}))
+	}
+	<-done
+}